@@ -0,0 +1,40 @@
+package lifxlan
+
+import "sync"
+
+// bufferSize is large enough to hold any LIFX datagram
+const bufferSize = 1500
+
+// bufferPool holds reusable datagram buffers for encoding and receiving packets
+var bufferPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, bufferSize)
+		return &b
+	},
+}
+
+// getBuffer returns a pooled buffer with a length of bufferSize
+func getBuffer() *[]byte {
+	b := bufferPool.Get().(*[]byte)
+	*b = (*b)[:bufferSize]
+	return b
+}
+
+// putBuffer returns a buffer obtained from getBuffer to the pool
+func putBuffer(b *[]byte) {
+	bufferPool.Put(b)
+}
+
+// grow extends dst by n zeroed bytes and returns the extended slice along with
+// the new bytes, reallocating only when dst lacks capacity
+func grow(dst []byte, n int) ([]byte, []byte) {
+	start := len(dst)
+	if cap(dst)-start >= n {
+		dst = dst[:start+n]
+		clear(dst[start:])
+	} else {
+		dst = append(dst, make([]byte, n)...)
+	}
+
+	return dst, dst[start:]
+}
@@ -1,12 +1,15 @@
 package lifxlan
 
 import (
-	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net"
+	"net/netip"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -14,15 +17,37 @@ import (
 
 var BroadcastAddress = net.UDPAddr{IP: net.IPv4bcast, Port: LifxPort}
 
+// ErrClientClosed is returned by operations on a client that is shutting down or closed
+var ErrClientClosed = errors.New("client is closed")
+
 type Client struct {
-	conn       *net.UDPConn
-	identifier uint32
-	mu         sync.Mutex
-	devices    []Device
+	conn         *net.UDPConn
+	identifier   uint32
+	devices      registry
+	imu          sync.RWMutex
+	interceptors []Interceptor
+
+	lifecycle  sync.Mutex     // Guards closing and registration of in-flight operations
+	closing    bool           // Set once shutdown starts, new operations are rejected
+	inflight   sync.WaitGroup // Operations that started before shutdown
+	done       chan struct{}  // Closed to cancel in-flight operations
+	cancelOnce sync.Once
+	closeOnce  sync.Once
+	closeErr   error
+
+	wmu        sync.Mutex      // Guards waiters and nextSeq
+	waiters    [256]chan frame // Requests awaiting responses, by sequence number
+	nextSeq    uint8           // Last sequence number handed out
+	readerDone chan struct{}   // Closed when readLoop returns
 }
 
 func NewClient() (*Client, error) {
-	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4zero, Port: LifxPort})
+	return newClient(&net.UDPAddr{IP: net.IPv4zero, Port: LifxPort})
+}
+
+// newClient creates a client bound to the given local address
+func newClient(laddr *net.UDPAddr) (*Client, error) {
+	conn, err := net.ListenUDP("udp4", laddr)
 	if err != nil {
 		return nil, fmt.Errorf("cannot bind to LIFX port: %w", err)
 	}
@@ -32,127 +57,316 @@ func NewClient() (*Client, error) {
 	client := &Client{
 		conn:       conn,
 		identifier: rand.Uint32(),
+		done:       make(chan struct{}),
+		readerDone: make(chan struct{}),
 	}
 
+	go client.readLoop()
+
 	return client, nil
 }
 
-// Close closes the UDP connection
+// Close stops accepting new operations, cancels in-flight operations and closes
+// the UDP connection. Cancelled operations return ErrClientClosed.
 func (c *Client) Close() error {
-	return c.conn.Close()
+	c.lifecycle.Lock()
+	c.closing = true
+	c.lifecycle.Unlock()
+
+	c.cancel()
+	c.inflight.Wait()
+
+	return c.closeConn()
+}
+
+// Shutdown stops accepting new operations and waits for in-flight operations,
+// such as sends waiting for a response, to finish before closing the UDP
+// connection. If ctx is done first the remaining operations are cancelled with
+// ErrClientClosed and the context's error is returned.
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.lifecycle.Lock()
+	c.closing = true
+	c.lifecycle.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		// Wake loops waiting outside an operation, such as Poll between ticks
+		c.cancel()
+		return c.closeConn()
+	case <-ctx.Done():
+		// Cancel what's left and wait for it to unwind before closing the socket
+		c.cancel()
+		<-finished
+		c.closeConn()
+		return ctx.Err()
+	}
+}
+
+// begin registers an in-flight operation, failing if the client is closing
+func (c *Client) begin() error {
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+
+	if c.closing {
+		return ErrClientClosed
+	}
+	c.inflight.Add(1)
+
+	return nil
+}
+
+// end marks an operation registered with begin as finished
+func (c *Client) end() {
+	c.inflight.Done()
+}
+
+// cancel signals in-flight operations to stop
+func (c *Client) cancel() {
+	c.cancelOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+// cancelled reports whether in-flight operations have been cancelled
+func (c *Client) cancelled() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// closeConn closes the UDP connection once and waits for the reader to stop
+func (c *Client) closeConn() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.conn.Close()
+		<-c.readerDone
+	})
+
+	return c.closeErr
+}
+
+// sleep pauses for the given duration, returning early with ErrClientClosed if the client is cancelled
+func (c *Client) sleep(d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-c.done:
+		return ErrClientClosed
+	}
 }
 
 // BroadcastPacket sends a packet to the broadcast address
 func (c *Client) BroadcastPacket(packet []byte) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	if err := c.begin(); err != nil {
+		return err
+	}
+	defer c.end()
 
-	// Send the packet to the broadcast address
-	_, err := c.conn.WriteToUDP(packet, &BroadcastAddress)
+	packet, addr, err := c.intercept(Outbound, packet, &BroadcastAddress)
 	if err != nil {
 		return fmt.Errorf("failed to send broadcast packet: %w", err)
 	}
 
+	// Send the packet to the broadcast address
+	if err := c.write(packet, addr); err != nil {
+		return fmt.Errorf("failed to send broadcast packet: %w", err)
+	}
+
 	return nil
 }
 
 // Send sends a packet to a specific address
 func (c *Client) Send(packet []byte, addr *net.UDPAddr) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	if err := c.begin(); err != nil {
+		return err
+	}
+	defer c.end()
+
+	packet, addr, err := c.intercept(Outbound, packet, addr)
+	if err != nil {
+		return fmt.Errorf("failed to send packet: %w", err)
+	}
+
+	return c.write(packet, addr)
+}
+
+// sendAddrPort sends a packet to a netip.AddrPort, which unlike Send doesn't
+// allocate when no interceptors are registered
+func (c *Client) sendAddrPort(packet []byte, addr netip.AddrPort) error {
+	if err := c.begin(); err != nil {
+		return err
+	}
+	defer c.end()
+
+	c.imu.RLock()
+	intercepted := len(c.interceptors) > 0
+	c.imu.RUnlock()
+
+	// Interceptors work with a *net.UDPAddr
+	if intercepted {
+		packet, udpAddr, err := c.intercept(Outbound, packet, net.UDPAddrFromAddrPort(addr))
+		if err != nil {
+			return fmt.Errorf("failed to send packet: %w", err)
+		}
+		return c.write(packet, udpAddr)
+	}
+
+	if c.cancelled() {
+		return ErrClientClosed
+	}
+
+	_, err := c.conn.WriteToUDPAddrPort(packet, addr)
+
+	return err
+}
+
+// write sends a datagram unless the client has been cancelled
+func (c *Client) write(packet []byte, addr *net.UDPAddr) error {
+	if c.cancelled() {
+		return ErrClientClosed
+	}
 
 	_, err := c.conn.WriteToUDP(packet, addr)
 
 	return err
 }
 
-// SendAndWait sends a packet and waits for a response
+// SendAndWait sends a packet and waits for a response. The response is matched
+// by a sequence number set on a copy of the packet, so the caller's is ignored.
 func (c *Client) SendAndWait(packet []byte, addr *net.UDPAddr, expectedType PacketType, timeout time.Duration) ([]byte, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	var response []byte
 
-	// fmt.Printf("Sending packet: %s\n", hex.EncodeToString(packet))
-	// fmt.Printf("Sending packet to %s\n", addr.String())
-	_, err := c.conn.WriteToUDP(packet, addr)
+	_, err := c.exchange(packet, addr, timeout, waiterQueue, func(h *Header, data []byte, _ *net.UDPAddr) bool {
+		if h.Type() != expectedType {
+			return false
+		}
+
+		// Copy the response out of the receive buffer
+		response = append([]byte(nil), data...)
+		return true
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to send packet: %w", err)
+		return nil, err
 	}
 
-	c.conn.SetReadDeadline(time.Now().Add(timeout))
-	buf := make([]byte, 1500)
-	for {
-		len, _, err := c.conn.ReadFromUDP(buf)
-		if err != nil {
-			if ne, ok := err.(net.Error); ok && ne.Timeout() {
-				return nil, fmt.Errorf("timeout waiting for response: %w", err)
-			}
-			return nil, fmt.Errorf("failed to read from UDP: %w", err)
-		}
+	return response, nil
+}
 
-		h, err := ParseHeader(buf[:len])
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse header: %w", err)
-		}
+// exchange sends a packet and passes each response to it to accept until
+// accept returns true. Responses are matched by the sequence number, which is
+// set on a copy of the packet, and are read by readLoop so exchanges can run
+// concurrently. Up to queue responses are held while accept runs, requests
+// answered by many packets need room for all of them. It returns the time the
+// packet was written.
+func (c *Client) exchange(packet []byte, addr *net.UDPAddr, timeout time.Duration, queue int, accept func(h *Header, data []byte, addr *net.UDPAddr) bool) (time.Time, error) {
+	if err := c.begin(); err != nil {
+		return time.Time{}, err
+	}
+	defer c.end()
 
-		if h.Source() == c.identifier && h.Type() == expectedType {
-			return buf[:len], nil
-		}
+	if len(packet) < HeaderSize {
+		return time.Time{}, ErrShortHeader
 	}
-}
 
-// Discover sends a discovery packet and listens for responses
-func (c *Client) Discover(timeout time.Duration) error {
-	packet := BuildDiscoveryPacket(c.identifier)
+	seq, frames, err := c.register(queue)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer c.unregister(seq)
 
-	// Send the discovery packet
-	if err := c.BroadcastPacket(packet); err != nil {
-		return fmt.Errorf("failed to send discovery packet: %w", err)
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	packet = append((*buf)[:0], packet...)
+	(*Header)(packet).SetSequence(seq)
+
+	packet, addr, err = c.intercept(Outbound, packet, addr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to send packet: %w", err)
 	}
 
-	// Set read deadline for responses
-	deadline := time.Now().Add(timeout)
-	c.conn.SetReadDeadline(deadline)
+	sent := time.Now()
+	if err := c.write(packet, addr); err != nil {
+		if errors.Is(err, ErrClientClosed) {
+			return sent, err
+		}
+		return sent, fmt.Errorf("failed to send packet: %w", err)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
 
-	// Listen for responses
-	buf := make([]byte, 1500)
 	for {
-		len, remote, err := c.conn.ReadFromUDP(buf)
-		if err != nil {
-			if ne, ok := err.(net.Error); ok && ne.Timeout() {
-				break // Timeout reached
+		select {
+		case f := <-frames:
+			// Frames are parsed before delivery, accept must copy anything it keeps
+			done := accept((*Header)(f.data), f.data, f.addr)
+			putBuffer(f.buf)
+			if done {
+				return sent, nil
 			}
-			return fmt.Errorf("failed to read from UDP: %w", err)
+		case <-timer.C:
+			return sent, fmt.Errorf("timeout waiting for response: %w", os.ErrDeadlineExceeded)
+		case <-c.done:
+			return sent, ErrClientClosed
 		}
+	}
+}
 
-		// Parse the header from the received packet
-		h, err := ParseHeader(buf[:len])
-		if err != nil {
-			return fmt.Errorf("failed to parse header: %w", err)
+// Discover sends a discovery packet and listens for responses
+func (c *Client) Discover(timeout time.Duration) error {
+	packet := BuildDiscoveryPacket(c.identifier)
+
+	// Collect responses until the timeout, which ends discovery rather than failing it
+	_, err := c.exchange(packet, &BroadcastAddress, timeout, waiterQueue, func(h *Header, data []byte, remote *net.UDPAddr) bool {
+		if h.Type() != StateService {
+			return false
 		}
 
-		// Check if the packet is a response to our discovery request
-		if h.Source() != c.identifier || h.Type() != StateService {
-			continue // Ignore other packets
+		// Devices advertise each service separately, only UDP is usable
+		var service StateServiceMessage
+		if err := service.UnmarshalBinary(data[HeaderSize:]); err != nil || service.Service != DeviceServiceUDP {
+			return false
+		}
+		if service.Port == 0 || service.Port > 0xFFFF {
+			return false
 		}
 
-		// Add the device to the list of discovered devices
-		device := NewDevice(h.Target(), remote.IP, c)
+		// Add the device to the list of discovered devices, copying the MAC
+		// address out of the receive buffer
+		mac := append([]byte(nil), h.Target()...)
+		device, added := c.devices.add(NewDevice(mac, remote.IP, c))
 
-		seen := false
+		// Record the advertised address, which may have changed for a known device.
+		// The address is the one reported by the inbound interceptors.
+		device.update(func(info *DeviceInfo) {
+			info.IP = remote.IP
+			info.Port = int(service.Port)
+		})
 
-		// Check if the device is already discovered
-		for _, device := range c.devices {
-			if bytes.Equal(device.MAC, h.Target()) {
-				seen = true
-				break
-			}
+		if added {
+			fmt.Printf("Discovered device: %s at %s\n", hex.EncodeToString(device.mac), device.UDPAddr().String())
 		}
 
-		// Add the new device to the list if new
-		if !seen {
-			c.devices = append(c.devices, *device)
-			fmt.Printf("Discovered device: %s at %s\n", hex.EncodeToString(device.MAC), device.IP.String())
-		}
+		return false
+	})
+	if err != nil && !isTimeout(err) {
+		return fmt.Errorf("failed to send discovery packet: %w", err)
 	}
 
 	// Retrieve device information
@@ -163,26 +377,35 @@ func (c *Client) Discover(timeout time.Duration) error {
 
 // LoadDevices loads devices from a JSON string
 func (c *Client) LoadDevices(jsonData string) error {
-	// Unmarshal the JSON data into a slice of Device structs
-	var devices []Device
-	if err := json.Unmarshal([]byte(jsonData), &devices); err != nil {
+	// Unmarshal the JSON data into a slice of DeviceInfo structs
+	var infos []DeviceInfo
+	if err := json.Unmarshal([]byte(jsonData), &infos); err != nil {
 		return fmt.Errorf("failed to unmarshal devices JSON: %w", err)
 	}
 
-	// Clear existing devices
-	c.mu.Lock()
-	c.devices = []Device{}
-
-	// Add each device to the client's device list
-	for _, device := range devices {
-		newDevice := NewDevice(device.MAC, device.IP, c)
-		newDevice.Label = device.Label
-		newDevice.Product = device.Product
+	// Create a device for each entry
+	devices := make([]*Device, 0, len(infos))
+	for _, info := range infos {
+		newDevice := NewDevice(info.MAC, info.IP, c)
+		newDevice.update(func(loaded *DeviceInfo) {
+			loaded.Label = info.Label
+			loaded.Product = info.Product
+			loaded.Kind = info.Product.Kind()
+			loaded.HostFirmware = info.HostFirmware
+			loaded.WifiFirmware = info.WifiFirmware
+
+			// Devices exported before ports were recorded use the default port
+			if info.Port != 0 {
+				loaded.Port = info.Port
+			}
+		})
 
-		c.devices = append(c.devices, *newDevice)
-		fmt.Printf("Loaded device: %s at %s\n", hex.EncodeToString(newDevice.MAC), newDevice.IP.String())
+		devices = append(devices, newDevice)
+		fmt.Printf("Loaded device: %s at %s\n", hex.EncodeToString(newDevice.mac), newDevice.UDPAddr().String())
 	}
-	c.mu.Unlock()
+
+	// Replace existing devices
+	c.devices.replace(devices)
 
 	// Refresh device information
 	c.RefreshDeviceInfo()
@@ -191,10 +414,9 @@ func (c *Client) LoadDevices(jsonData string) error {
 }
 
 func (c *Client) RefreshDeviceInfo() {
-	for i := range c.devices {
-		device := &c.devices[i]
+	for _, device := range c.devices.list() {
 		if err := device.RefreshInfo(); err != nil {
-			fmt.Printf("Error refreshing device info for %s: %v\n", hex.EncodeToString(device.MAC), err)
+			fmt.Printf("Error refreshing device info for %s: %v\n", hex.EncodeToString(device.mac), err)
 			continue
 		}
 	}
@@ -202,10 +424,7 @@ func (c *Client) RefreshDeviceInfo() {
 
 // ExportJSON exports the discovered devices as a JSON string
 func (c *Client) ExportJSON() string {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	jsonBytes, err := json.MarshalIndent(c.devices, "", "  ")
+	jsonBytes, err := json.MarshalIndent(c.Snapshot(), "", "  ")
 	if err != nil {
 		fmt.Printf("Error encoding JSON: %v\n", err)
 		return "[]"
@@ -214,30 +433,69 @@ func (c *Client) ExportJSON() string {
 	return string(jsonBytes)
 }
 
-// Devices returns the list of discovered devices
-func (c *Client) GetDevices() []Device {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// GetDevices returns the discovered devices in the order they were found.
+// The returned slice is a copy, the devices themselves are shared with the client.
+func (c *Client) GetDevices() []*Device {
+	return c.devices.list()
+}
 
-	return c.devices
+// GetDevicesByKind returns the devices of the given kind, such as lights or relay switches
+func (c *Client) GetDevicesByKind(kind DeviceKind) []*Device {
+	var devices []*Device
+	for _, device := range c.devices.list() {
+		if device.Info().Kind == kind {
+			devices = append(devices, device)
+		}
+	}
+
+	return devices
+}
+
+// Snapshot returns a copy of the information of every discovered device
+func (c *Client) Snapshot() []DeviceInfo {
+	devices := c.devices.list()
+
+	infos := make([]DeviceInfo, len(devices))
+	for i, device := range devices {
+		infos[i] = device.Info()
+	}
+	return infos
+}
+
+// RangeDevices calls fn for each discovered device until it returns false
+func (c *Client) RangeDevices(fn func(device *Device) bool) {
+	for _, device := range c.devices.list() {
+		if !fn(device) {
+			return
+		}
+	}
 }
 
 // ClearDevices clears the list of discovered devices
 func (c *Client) ClearDevices() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.devices.clear()
+}
 
-	c.devices = []Device{}
+// GetDeviceByMAC returns the device with the given MAC address, such as "d0:73:d5:01:02:03"
+func (c *Client) GetDeviceByMAC(mac string) (*Device, error) {
+	hw, err := net.ParseMAC(mac)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAC address %s: %w", mac, err)
+	}
+
+	device := c.devices.get(hw)
+	if device == nil {
+		return nil, fmt.Errorf("device with MAC address %s not found", mac)
+	}
+
+	return device, nil
 }
 
 func (c *Client) GetDeviceByLabel(label string) (*Device, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	for _, device := range c.devices {
+	for _, device := range c.devices.list() {
 		// Compare lowercase and trimmed labels
-		if SanitizeLabel(device.Label) == SanitizeLabel(label) {
-			return &device, nil
+		if SanitizeLabel(device.Info().Label) == SanitizeLabel(label) {
+			return device, nil
 		}
 	}
 
@@ -261,12 +519,11 @@ func SanitizeLabel(label string) string {
 
 // GetDeviceLabels returns a list of labels for all discovered devices
 func (c *Client) GetDeviceLabels() []string {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	devices := c.devices.list()
 
-	labels := make([]string, len(c.devices))
-	for i, device := range c.devices {
-		labels[i] = device.Label
+	labels := make([]string, len(devices))
+	for i, device := range devices {
+		labels[i] = device.Info().Label
 	}
 	return labels
 }
@@ -287,10 +544,28 @@ func (c *Client) TurnOff(label string) error {
 		return err
 	}
 
-	fmt.Printf("Turning off device: %s\n", device.Label)
+	fmt.Printf("Turning off device: %s\n", device.Info().Label)
 	return device.TurnOff()
 }
 
+// SetPowerWithDuration turns the device that matches the given label on or off over the given duration
+func (c *Client) SetPowerWithDuration(label string, on bool, duration time.Duration) error {
+	device, err := c.GetDeviceByLabel(label)
+	if err != nil {
+		return err
+	}
+	return device.SetPowerWithDuration(on, duration)
+}
+
+// IsOn returns true if the device that matches the given label is powered on
+func (c *Client) IsOn(label string) (bool, error) {
+	device, err := c.GetDeviceByLabel(label)
+	if err != nil {
+		return false, err
+	}
+	return device.IsOn()
+}
+
 // SetColor sets the color of the device that matches the given label
 func (c *Client) SetColor(label string, color LIFXColor, duration time.Duration) error {
 	device, err := c.GetDeviceByLabel(label)
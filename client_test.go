@@ -0,0 +1,339 @@
+package lifxlan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDiscoverConcurrentWithQueries(t *testing.T) {
+	discovered := newFakeDevice(t, 1)
+	known := newFakeDevice(t, 2)
+	useBroadcastAddress(t, discovered.addr())
+
+	c := newTestClient(t)
+	device := known.device(c)
+	known.reply(GetLabel, &StateLabelMessage{Label: labelBytes("Known")})
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := c.Discover(300 * time.Millisecond); err != nil {
+			t.Errorf("Discover: %v", err)
+		}
+	}()
+
+	// Replies to queries must reach them while discovery is listening
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				label, err := device.GetLabel()
+				if err != nil {
+					t.Errorf("GetLabel: %v", err)
+					return
+				}
+				if label != "Known" {
+					t.Errorf("label %q, want %q", label, "Known")
+				}
+				_ = device.Info()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	found, err := c.GetDeviceByMAC(net.HardwareAddr(discovered.mac).String())
+	if err != nil {
+		t.Fatalf("device answering discovery wasn't added: %v", err)
+	}
+	if mac := found.Info().MAC; !bytes.Equal(mac, discovered.mac) {
+		t.Errorf("discovered device has MAC %x, want %x", mac, discovered.mac)
+	}
+}
+
+func TestServicePortRoundTrip(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	useBroadcastAddress(t, fake.addr())
+	port := fake.addr().Port
+
+	c := newTestClient(t)
+	if err := c.Discover(200 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	mac := net.HardwareAddr(fake.mac).String()
+	device, err := c.GetDeviceByMAC(mac)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := device.Info().Port; got != port {
+		t.Fatalf("discovered port %d, want the advertised %d", got, port)
+	}
+
+	// The port survives an export and reload, and later requests are sent to it
+	loaded := newTestClient(t)
+	if err := loaded.LoadDevices(c.ExportJSON()); err != nil {
+		t.Fatal(err)
+	}
+	device, err = loaded.GetDeviceByMAC(mac)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := device.UDPAddr().Port; got != port {
+		t.Errorf("loaded device sends to port %d, want %d", got, port)
+	}
+	if label, err := device.GetLabel(); err != nil || label != "Fake" {
+		t.Errorf("GetLabel through the loaded port returned %q, %v", label, err)
+	}
+}
+
+func TestLoadDevicesWithoutPort(t *testing.T) {
+	// Exports made before ports were recorded have no port
+	data, err := json.Marshal([]DeviceInfo{{MAC: []byte{0xd0, 0x73, 0xd5, 0, 0, 1}, IP: net.IPv4(127, 0, 0, 1)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Record where frames are sent instead of waiting for a device that isn't there
+	var mu sync.Mutex
+	var ports []int
+	c := newTestClient(t)
+	c.Use(func(p *Packet) error {
+		mu.Lock()
+		defer mu.Unlock()
+		ports = append(ports, p.Addr.Port)
+		return ErrDropPacket
+	})
+
+	if err := c.LoadDevices(strings.Replace(string(data), `"port":0,`, "", 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if port := c.GetDevices()[0].Info().Port; port != LifxPort {
+		t.Errorf("loaded port %d, want %d", port, LifxPort)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ports) == 0 {
+		t.Fatal("no frames sent to the loaded device")
+	}
+	for _, port := range ports {
+		if port != LifxPort {
+			t.Errorf("frame sent to port %d, want %d", port, LifxPort)
+		}
+	}
+}
+
+// waitForRequest blocks until the fake device has received a packet of type pt
+func waitForRequest(t *testing.T, f *fakeDevice, pt PacketType) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		for _, frame := range f.requests() {
+			if h, _ := ParseHeader(frame); h.Type() == pt {
+				return
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("device didn't receive %v", pt)
+}
+
+func TestCloseCancelsBlockedSendAndWait(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.handle(GetLabel, nil)
+	c := newTestClient(t)
+
+	errs := make(chan error)
+	go func() {
+		packet, _ := BuildMessagePacket(c.identifier, fake.mac, &GetLabelMessage{})
+		_, err := c.SendAndWait(packet, fake.addr(), StateLabel, time.Minute)
+		errs <- err
+	}()
+
+	waitForRequest(t, fake, GetLabel)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrClientClosed) {
+			t.Errorf("SendAndWait returned %v, want ErrClientClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SendAndWait still blocked after Close")
+	}
+}
+
+func TestShutdownWaitsForInflight(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.handle(GetLabel, func([]byte) []Message {
+		time.Sleep(100 * time.Millisecond)
+		return []Message{&StateLabelMessage{Label: labelBytes("Slow")}}
+	})
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	type result struct {
+		label string
+		err   error
+	}
+	results := make(chan result, 1)
+	go func() {
+		label, err := device.GetLabel()
+		results <- result{label, err}
+	}()
+
+	waitForRequest(t, fake, GetLabel)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	// The request finished before Shutdown returned
+	select {
+	case r := <-results:
+		if r.err != nil || r.label != "Slow" {
+			t.Errorf("GetLabel returned %q, %v, want it to complete", r.label, r.err)
+		}
+	default:
+		t.Fatal("Shutdown returned before the in-flight request finished")
+	}
+}
+
+func TestShutdownCancelsWhenContextExpires(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.handle(GetLabel, nil)
+	c := newTestClient(t)
+
+	errs := make(chan error, 1)
+	go func() {
+		packet, _ := BuildMessagePacket(c.identifier, fake.mac, &GetLabelMessage{})
+		_, err := c.SendAndWait(packet, fake.addr(), StateLabel, time.Minute)
+		errs <- err
+	}()
+
+	waitForRequest(t, fake, GetLabel)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := c.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown returned %v, want context.DeadlineExceeded", err)
+	}
+
+	// Shutdown waits for cancelled operations to unwind before returning
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrClientClosed) {
+			t.Errorf("SendAndWait returned %v, want ErrClientClosed", err)
+		}
+	default:
+		t.Fatal("Shutdown returned before the cancelled request finished")
+	}
+}
+
+func TestShutdownStopsPoll(t *testing.T) {
+	c := newTestClient(t)
+
+	// With no devices Poll only waits for the next tick
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.Poll(context.Background(), PollOptions{Interval: time.Hour})
+	}()
+
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrClientClosed) {
+			t.Errorf("Poll returned %v, want ErrClientClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Poll still running after Shutdown")
+	}
+}
+
+func TestClosedClientRejectsCalls(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	packet, _ := BuildMessagePacket(c.identifier, fake.mac, &GetLabelMessage{})
+
+	calls := []struct {
+		name string
+		fn   func() error
+	}{
+		{"Send", func() error { return c.Send(packet, fake.addr()) }},
+		{"SendAndWait", func() error {
+			_, err := c.SendAndWait(packet, fake.addr(), StateLabel, time.Second)
+			return err
+		}},
+		{"BroadcastPacket", func() error { return c.BroadcastPacket(packet) }},
+		{"Discover", func() error { return c.Discover(time.Second) }},
+		{"SetColor", func() error { return device.SetColor(NewColor(0, 0, 0, 3500), 0) }},
+		{"GetLabel", func() error {
+			_, err := device.GetLabel()
+			return err
+		}},
+	}
+
+	for _, call := range calls {
+		if err := call.fn(); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("%s after Close returned %v, want ErrClientClosed", call.name, err)
+		}
+	}
+
+	if len(fake.requests()) != 0 {
+		t.Errorf("device received %d packets after Close", len(fake.requests()))
+	}
+}
+
+func TestCloseStopsGoroutines(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	before := runtime.NumGoroutine()
+
+	c, err := newClient(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	device := fake.device(c)
+
+	if _, err := device.GetLabel(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Exited goroutines can take a moment to be accounted for
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("%d goroutines after Close, want at most %d", n, before)
+	}
+}
@@ -0,0 +1,55 @@
+// Command lifxdiag discovers LIFX devices and reports echo round trip
+// statistics for each of them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/murphy28/lifxlan"
+)
+
+func main() {
+	count := flag.Int("count", 10, "number of echo requests per device")
+	interval := flag.Duration("interval", 200*time.Millisecond, "time between echo requests")
+	discover := flag.Duration("discover", 5*time.Second, "how long to wait for discovery responses")
+	flag.Parse()
+
+	// Create a new LIFX client
+	lifx, err := lifxlan.NewClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error creating LIFX client:", err)
+		os.Exit(1)
+	}
+	defer lifx.Close()
+
+	// Discover LIFX devices on the network
+	if err := lifx.Discover(*discover); err != nil {
+		fmt.Fprintln(os.Stderr, "Error discovering LIFX devices:", err)
+		os.Exit(1)
+	}
+
+	report := lifx.Diagnose(*count, *interval)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LABEL\tMAC\tIP\tLOSS\tMIN\tAVG\tMAX\tP95\tJITTER\tRSSI\tSIGNAL")
+	for _, health := range report {
+		if health.Err != nil {
+			fmt.Fprintf(w, "%s\t%s\t%s\terror: %v\n", health.Label, health.MAC, health.IP, health.Err)
+			continue
+		}
+
+		rssi, signal := "-", "unknown"
+		if health.Wifi != nil {
+			rssi, signal = fmt.Sprintf("%d dBm", health.Wifi.RSSI), health.Wifi.Quality.String()
+		}
+
+		p := health.Ping
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.1f%%\t%v\t%v\t%v\t%v\t%v\t%s\t%s\n",
+			health.Label, health.MAC, health.IP, p.Loss, p.Min, p.Avg, p.Max, p.P95, p.Jitter, rssi, signal)
+	}
+	w.Flush()
+}
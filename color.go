@@ -1,6 +1,7 @@
 package lifxlan
 
 import (
+	"encoding/json"
 	"math"
 
 	"github.com/lucasb-eyer/go-colorful"
@@ -22,6 +23,30 @@ func NewColor(hue, saturation, brightness, kelvin uint16) LIFXColor {
 	}
 }
 
+// colorJSON is the JSON form of a LIFXColor using the raw protocol values
+type colorJSON struct {
+	Hue        uint16 `json:"hue"`
+	Saturation uint16 `json:"saturation"`
+	Brightness uint16 `json:"brightness"`
+	Kelvin     uint16 `json:"kelvin"`
+}
+
+// MarshalJSON encodes the raw protocol values of the color
+func (c LIFXColor) MarshalJSON() ([]byte, error) {
+	return json.Marshal(colorJSON{Hue: c.hue, Saturation: c.saturation, Brightness: c.brightness, Kelvin: c.kelvin})
+}
+
+// UnmarshalJSON decodes the raw protocol values of the color
+func (c *LIFXColor) UnmarshalJSON(data []byte) error {
+	var v colorJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*c = NewColor(v.Hue, v.Saturation, v.Brightness, v.Kelvin)
+	return nil
+}
+
 func (c *LIFXColor) SetHue(hue float64) {
 	c.hue = uint16(int((math.Round(0x10000*hue) / 360)) % 0x10000)
 }
@@ -2,35 +2,98 @@ package lifxlan
 
 import (
 	"bytes"
-	"crypto/rand"
-	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/netip"
+	"sync"
 	"time"
 )
 
+// DeviceInfo holds the information known about a device
+type DeviceInfo struct {
+	MAC     []byte     `json:"mac"`
+	IP      net.IP     `json:"ip"`
+	Port    int        `json:"port"` // UDP port advertised by the device in StateService
+	Label   string     `json:"label"`
+	Product Product    `json:"product"`
+	Kind    DeviceKind `json:"kind,omitempty"` // Derived from the product
+	Power   uint16     `json:"power"`          // Power level last reported by the device, 0 is off and 65535 is on
+	Color   LIFXColor  `json:"color"`          // Color last reported by the device
+
+	HostFirmware FirmwareVersion `json:"host_firmware"`
+	WifiFirmware FirmwareVersion `json:"wifi_firmware"`
+
+	AmbientLight float64 `json:"ambient_light,omitempty"` // Lux last measured by the light sensor
+}
+
+// responseTimeout is how long to wait for a device to answer a query
+const responseTimeout = 2 * time.Second
+
+// Device is a LIFX device known to a Client.
+//
+// Its information is updated as the device is discovered and queried, use
+// Info to read a consistent copy while other goroutines may be updating it.
 type Device struct {
-	MAC     []byte `json:"mac"`
-	IP      net.IP `json:"ip"`
-	client  *Client
-	Label   string  `json:"label"`
-	Product Product `json:"product"`
+	info   DeviceInfo
+	mac    []byte         // Copy of info.MAC, which never changes, for use without the lock
+	addr   netip.AddrPort // Derived from info.IP and info.Port so sends don't allocate
+	client *Client
+	mu     sync.RWMutex // Guards info, addr and noSensor
+
+	noSensor bool // Set once the device reports it has no ambient light sensor
 }
 
 func NewDevice(mac []byte, ip net.IP, c *Client) *Device {
 	device := &Device{
-		MAC:    mac,
-		IP:     ip,
+		info: DeviceInfo{
+			MAC:  mac,
+			IP:   ip,
+			Port: LifxPort,
+		},
+		mac:    mac,
+		addr:   addrPort(ip, LifxPort),
 		client: c,
 	}
 
 	return device
 }
 
+// Info returns a copy of the device information
+func (d *Device) Info() DeviceInfo {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.info
+}
+
+// update modifies the device information while holding the device lock
+func (d *Device) update(fn func(info *DeviceInfo)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	fn(&d.info)
+	d.addr = addrPort(d.info.IP, d.info.Port)
+}
+
+// addrPort converts an IP address and port to a netip.AddrPort, using the LIFX port if port is 0
+func addrPort(ip net.IP, port int) netip.AddrPort {
+	if port == 0 {
+		port = LifxPort
+	}
+
+	addr, _ := netip.AddrFromSlice(ip)
+	return netip.AddrPortFrom(addr.Unmap(), uint16(port))
+}
+
+// MarshalJSON encodes a copy of the device information
+func (d *Device) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Info())
+}
+
 // ExportJSON exports the device information as a JSON string using marshall
 func (d *Device) ExportDeviceJSON() string {
-	jsonBytes, err := json.MarshalIndent(d, "", "  ")
+	jsonBytes, err := json.MarshalIndent(d.Info(), "", "  ")
 	if err != nil {
 		fmt.Printf("Error encoding JSON: %v\n", err)
 		return "{}"
@@ -58,46 +121,154 @@ func (d *Device) RefreshInfo() error {
 		return fmt.Errorf("failed to get product for device %s: %w", d.GetMACAddress(), err)
 	}
 
+	// Obtain current firmware versions
+	_, err = d.GetHostFirmware()
+	if err != nil {
+		return fmt.Errorf("failed to get host firmware for device %s: %w", d.GetMACAddress(), err)
+	}
+
+	_, err = d.GetWifiFirmware()
+	if err != nil {
+		return fmt.Errorf("failed to get wifi firmware for device %s: %w", d.GetMACAddress(), err)
+	}
+
+	// Obtain current power level
+	_, err = d.GetPower()
+	if err != nil {
+		return fmt.Errorf("failed to get power for device %s: %w", d.GetMACAddress(), err)
+	}
+
 	return nil
 }
 
 func (d *Device) Send(packet []byte) error {
-	return d.client.Send(packet, &net.UDPAddr{
-		IP:   d.IP,
-		Port: LifxPort,
-	})
+	d.mu.RLock()
+	addr := d.addr
+	d.mu.RUnlock()
+
+	return d.client.sendAddrPort(packet, addr)
 }
 
 func (d *Device) SendAndWait(packet []byte, pktType PacketType, duration time.Duration) ([]byte, error) {
 	return d.client.SendAndWait(packet, d.UDPAddr(), pktType, duration)
 }
 
+// send encodes a message into a pooled buffer and sends it to the device
+func (d *Device) send(msg Message) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	packet, err := AppendMessagePacket((*buf)[:0], d.client.identifier, d.mac, msg)
+	if err != nil {
+		return err
+	}
+
+	return d.Send(packet)
+}
+
+// request sends a message to the device and decodes its response into reply
+func (d *Device) request(msg, reply Message) error {
+	return d.collect(msg, waiterQueue, func(h *Header, payload []byte) (bool, error) {
+		if h.Type() != reply.Type() {
+			return false, nil
+		}
+
+		return true, reply.UnmarshalBinary(payload)
+	})
+}
+
+// collect sends a message to the device and passes each response from the
+// device to handle, until handle reports that it is done or fails. Up to queue
+// responses are held while handle runs. If the device answers that it doesn't
+// handle the message, ErrUnsupported is returned.
+func (d *Device) collect(msg Message, queue int, handle func(h *Header, payload []byte) (bool, error)) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	packet, err := AppendMessagePacket((*buf)[:0], d.client.identifier, d.mac, msg)
+	if err != nil {
+		return err
+	}
+
+	var handleErr error
+	_, err = d.client.exchange(packet, d.UDPAddr(), responseTimeout, queue, func(h *Header, data []byte, _ *net.UDPAddr) bool {
+		// Only accept responses from this device
+		if !bytes.Equal(h.Target(), d.mac) {
+			return false
+		}
+
+		// Devices reply with StateUnhandled to messages they don't know
+		if h.Type() == StateUnhandled {
+			var unhandled StateUnhandledMessage
+			if err := unhandled.UnmarshalBinary(data[HeaderSize:]); err != nil || PacketType(unhandled.UnhandledType) != msg.Type() {
+				return false
+			}
+
+			handleErr = fmt.Errorf("%w: %v on %s", ErrUnsupported, msg.Type(), d.GetMACAddress())
+			return true
+		}
+
+		done, err := handle(h, data[HeaderSize:])
+		if err != nil {
+			handleErr = err
+			return true
+		}
+		return done
+	})
+	if err != nil {
+		return err
+	}
+
+	return handleErr
+}
+
 func (d *Device) TurnOn() error {
-	packet := BuildSetPowerPacket(d.client.identifier, d.MAC, true)
+	// Switches have no light, so operate their relays instead
+	if d.Info().Kind == KindRelay {
+		return d.setAllRelays(true)
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	packet := AppendSetPowerPacket((*buf)[:0], d.client.identifier, d.mac, true)
 	return d.Send(packet)
 }
 
 func (d *Device) TurnOff() error {
-	packet := BuildSetPowerPacket(d.client.identifier, d.MAC, false)
+	// Switches have no light, so operate their relays instead
+	if d.Info().Kind == KindRelay {
+		return d.setAllRelays(false)
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	packet := AppendSetPowerPacket((*buf)[:0], d.client.identifier, d.mac, false)
 	return d.Send(packet)
 }
 
 func (d *Device) SetColor(color LIFXColor, duration time.Duration) error {
-	packet := BuildSetColorPacket(d.client.identifier, d.MAC, color, duration)
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	packet := AppendSetColorPacket((*buf)[:0], d.client.identifier, d.mac, color, duration)
 	return d.Send(packet)
 }
 
 func (d *Device) GetLabel() (string, error) {
-	packet := BuildGetLabelPacket(d.client.identifier, d.MAC)
-	response, err := d.SendAndWait(packet, StateLabel, 2*time.Second)
-	if err != nil {
+	var state StateLabelMessage
+	if err := d.request(&GetLabelMessage{}, &state); err != nil {
 		return "", err
 	}
 
-	// The label is in bytes 36-52 of the response
-	labelBytes := bytes.TrimRight(response[HeaderSize:HeaderSize+32], "\x00") // Get the raw bytes
-	label := string(labelBytes)
-	d.Label = label // Update the device's label field
+	label := labelString(state.Label)
+
+	// Update the device's label field
+	d.update(func(info *DeviceInfo) {
+		info.Label = label
+	})
+
 	return label, nil
 }
 
@@ -107,55 +278,53 @@ func (d *Device) SetLabel(label string) error {
 		label = label[:32]
 	}
 
-	packet := BuildSetLabelPacket(d.client.identifier, d.MAC, label)
+	packet := BuildSetLabelPacket(d.client.identifier, d.mac, label)
 	return d.Send(packet)
 }
 
 func (d *Device) GetProduct() (Product, error) {
-	packet := BuildGetVersionPacket(d.client.identifier, d.MAC)
-	response, err := d.SendAndWait(packet, StateVersion, 2*time.Second)
-	if err != nil {
+	var state StateVersionMessage
+	if err := d.request(&GetVersionMessage{}, &state); err != nil {
 		return Product{}, err
 	}
 
-	vendorID := binary.LittleEndian.Uint32(response[HeaderSize : HeaderSize+4])
-	productID := binary.LittleEndian.Uint32(response[HeaderSize+4 : HeaderSize+8])
-
-	product, err := GetProduct(int(vendorID), int(productID))
-
+	product, err := GetProduct(int(state.Vendor), int(state.Product))
 	if err != nil {
 		return Product{}, err
 	}
 
-	d.Product = product
+	d.update(func(info *DeviceInfo) {
+		info.Product = product
+		info.Kind = product.Kind()
+	})
 
 	return product, nil
 }
 
 func (d *Device) Ping() bool {
-	uniquePayload := make([]byte, 64)
-	rand.Read(uniquePayload)
-
-	packet := BuildEchoRequestPacket(d.client.identifier, d.MAC, uniquePayload)
-
-	response, err := d.SendAndWait(packet, EchoResponse, 2*time.Second)
-	if err != nil {
+	if _, err := d.echo(echoTimeout); err != nil {
 		fmt.Printf("Error sending echo request to device %s: %v\n", d.GetMACAddress(), err)
 		return false
 	}
 
-	echoing := response[HeaderSize : HeaderSize+64]
-
-	return bytes.Equal(echoing, uniquePayload)
+	return true
 }
 
 func (d *Device) GetMACAddress() string {
-	return net.HardwareAddr(d.MAC).String()
+	return net.HardwareAddr(d.mac).String()
 }
 
 func (d *Device) UDPAddr() *net.UDPAddr {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	port := d.info.Port
+	if port == 0 {
+		port = LifxPort
+	}
+
 	return &net.UDPAddr{
-		IP:   d.IP,
-		Port: LifxPort,
+		IP:   d.info.IP,
+		Port: port,
 	}
 }
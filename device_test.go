@@ -0,0 +1,52 @@
+package lifxlan
+
+import (
+	"testing"
+)
+
+// TestDeviceSendDoesNotAllocate checks the hot paths used to drive lights at a
+// high frame rate. The fake device receives the packets on the loopback interface.
+func TestDeviceSendDoesNotAllocate(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	// Extended multizone needs the product to be known
+	fake.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 117})
+	if _, err := device.Features(); err != nil {
+		t.Fatal(err)
+	}
+
+	color := NewColor(21845, 65535, 65535, 3500)
+	colors := make([]LIFXColor, maxExtendedZones)
+	for i := range colors {
+		colors[i] = color
+	}
+	msg := &SetPowerMessage{Level: 65535}
+
+	tests := []struct {
+		name string
+		fn   func() error
+	}{
+		{"SetColor", func() error { return device.SetColor(color, 0) }},
+		{"send", func() error { return device.send(msg) }},
+		{"SetExtendedColorZones", func() error {
+			return device.SetExtendedColorZones(0, colors, 0, MultiZoneExtendedApplicationRequestApply)
+		}},
+	}
+
+	for _, tt := range tests {
+		var err error
+		allocs := testing.AllocsPerRun(100, func() {
+			if e := tt.fn(); e != nil {
+				err = e
+			}
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if allocs != 0 {
+			t.Errorf("%s allocates %.1f times per call, want 0", tt.name, allocs)
+		}
+	}
+}
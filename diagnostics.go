@@ -0,0 +1,177 @@
+package lifxlan
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"time"
+)
+
+// echoTimeout is how long to wait for each echo response
+const echoTimeout = 2 * time.Second
+
+// PingStats summarises the round trip times of a series of echo requests
+type PingStats struct {
+	Sent     int           `json:"sent"`
+	Received int           `json:"received"`
+	Loss     float64       `json:"loss"` // Percentage of requests without a response
+	Min      time.Duration `json:"min"`
+	Avg      time.Duration `json:"avg"`
+	Max      time.Duration `json:"max"`
+	P95      time.Duration `json:"p95"`
+	Jitter   time.Duration `json:"jitter"` // Mean difference between consecutive round trips
+}
+
+// String formats the statistics on a single line
+func (s PingStats) String() string {
+	return fmt.Sprintf("%d/%d received, %.1f%% loss, min/avg/max/p95 = %v/%v/%v/%v, jitter %v",
+		s.Received, s.Sent, s.Loss, s.Min, s.Avg, s.Max, s.P95, s.Jitter)
+}
+
+// DeviceHealth is the diagnostics result for a single device
+type DeviceHealth struct {
+	MAC   string    `json:"mac"`
+	IP    net.IP    `json:"ip"`
+	Label string    `json:"label"`
+	Ping  PingStats `json:"ping"`
+	Wifi  *WifiInfo `json:"wifi,omitempty"` // Nil if the device didn't report its signal
+	Err   error     `json:"-"`              // Set if the device could not be measured
+}
+
+// echo sends a single echo request with a random payload and returns the round trip time
+func (d *Device) echo(timeout time.Duration) (time.Duration, error) {
+	payload := make([]byte, 64)
+	rand.Read(payload)
+
+	packet := BuildEchoRequestPacket(d.client.identifier, d.mac, payload)
+
+	var received time.Time
+	sent, err := d.client.exchange(packet, d.UDPAddr(), timeout, waiterQueue, func(h *Header, data []byte, _ *net.UDPAddr) bool {
+		// Ignore late responses to earlier requests
+		if h.Type() != EchoResponse || !bytes.Equal(data[HeaderSize:], payload) {
+			return false
+		}
+
+		received = time.Now()
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return received.Sub(sent), nil
+}
+
+// Diagnose sends count echo requests spaced by interval and reports round trip statistics
+func (d *Device) Diagnose(count int, interval time.Duration) (PingStats, error) {
+	if count <= 0 {
+		return PingStats{}, errors.New("echo count must be positive")
+	}
+
+	rtts := make([]time.Duration, 0, count)
+	for i := 0; i < count; i++ {
+		start := time.Now()
+
+		rtt, err := d.echo(echoTimeout)
+		if err == nil {
+			rtts = append(rtts, rtt)
+		} else if !isTimeout(err) {
+			return PingStats{}, fmt.Errorf("failed to ping device %s: %w", d.GetMACAddress(), err)
+		}
+
+		// Wait out the rest of the interval before the next request
+		if i < count-1 {
+			if err := d.client.sleep(interval - time.Since(start)); err != nil {
+				return PingStats{}, err
+			}
+		}
+	}
+
+	return summarisePings(count, rtts), nil
+}
+
+// Diagnose measures every known device concurrently and returns one entry per device.
+// Responses are matched to their requests, so a device that doesn't answer only
+// delays its own entry.
+func (c *Client) Diagnose(count int, interval time.Duration) []DeviceHealth {
+	devices := c.GetDevices()
+	report := make([]DeviceHealth, len(devices))
+
+	var wg sync.WaitGroup
+	for i, device := range devices {
+		info := device.Info()
+		report[i] = DeviceHealth{
+			MAC:   device.GetMACAddress(),
+			IP:    info.IP,
+			Label: info.Label,
+		}
+
+		wg.Add(1)
+		go func(health *DeviceHealth) {
+			defer wg.Done()
+			health.Ping, health.Err = device.Diagnose(count, interval)
+			if health.Err != nil {
+				return
+			}
+
+			// The signal shows where access points are needed
+			if wifi, err := device.GetWifiInfo(); err == nil {
+				health.Wifi = &wifi
+			}
+		}(&report[i])
+	}
+	wg.Wait()
+
+	return report
+}
+
+// summarisePings computes statistics from the round trip times of received responses
+func summarisePings(sent int, rtts []time.Duration) PingStats {
+	stats := PingStats{
+		Sent:     sent,
+		Received: len(rtts),
+		Loss:     float64(sent-len(rtts)) / float64(sent) * 100,
+	}
+	if len(rtts) == 0 {
+		return stats
+	}
+
+	// Jitter is measured over the responses in the order they were received
+	var total, jitter time.Duration
+	for i, rtt := range rtts {
+		total += rtt
+		if i > 0 {
+			diff := rtt - rtts[i-1]
+			if diff < 0 {
+				diff = -diff
+			}
+			jitter += diff
+		}
+	}
+	if len(rtts) > 1 {
+		stats.Jitter = jitter / time.Duration(len(rtts)-1)
+	}
+
+	sorted := slices.Clone(rtts)
+	slices.Sort(sorted)
+
+	// Nearest-rank 95th percentile
+	rank := (95*len(sorted) + 99) / 100
+
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+	stats.Avg = total / time.Duration(len(sorted))
+	stats.P95 = sorted[rank-1]
+
+	return stats
+}
+
+// isTimeout reports whether err was caused by a network timeout
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
@@ -0,0 +1,102 @@
+package lifxlan
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarisePings(t *testing.T) {
+	ms := time.Millisecond
+
+	tests := []struct {
+		name string
+		sent int
+		rtts []time.Duration
+		want PingStats
+	}{
+		{
+			name: "none received",
+			sent: 3,
+			want: PingStats{Sent: 3, Loss: 100},
+		},
+		{
+			name: "one received",
+			sent: 2,
+			rtts: []time.Duration{10 * ms},
+			want: PingStats{Sent: 2, Received: 1, Loss: 50, Min: 10 * ms, Avg: 10 * ms, Max: 10 * ms, P95: 10 * ms},
+		},
+		{
+			// Jitter follows the order received: |30-10|, |20-30| and |40-20|
+			name: "unordered",
+			sent: 4,
+			rtts: []time.Duration{10 * ms, 30 * ms, 20 * ms, 40 * ms},
+			want: PingStats{Sent: 4, Received: 4, Min: 10 * ms, Avg: 25 * ms, Max: 40 * ms, P95: 40 * ms, Jitter: 50 * ms / 3},
+		},
+		{
+			// The nearest rank for the 95th percentile of 20 samples is the 19th
+			name: "percentile",
+			sent: 20,
+			rtts: []time.Duration{
+				1 * ms, 2 * ms, 3 * ms, 4 * ms, 5 * ms, 6 * ms, 7 * ms, 8 * ms, 9 * ms, 10 * ms,
+				11 * ms, 12 * ms, 13 * ms, 14 * ms, 15 * ms, 16 * ms, 17 * ms, 18 * ms, 19 * ms, 20 * ms,
+			},
+			want: PingStats{Sent: 20, Received: 20, Min: 1 * ms, Avg: 10500 * time.Microsecond, Max: 20 * ms, P95: 19 * ms, Jitter: 1 * ms},
+		},
+		{
+			name: "loss",
+			sent: 8,
+			rtts: []time.Duration{5 * ms, 5 * ms},
+			want: PingStats{Sent: 8, Received: 2, Loss: 75, Min: 5 * ms, Avg: 5 * ms, Max: 5 * ms, P95: 5 * ms},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarisePings(tt.sent, tt.rtts); got != tt.want {
+				t.Errorf("summarisePings(%d, %v)\n got %+v\nwant %+v", tt.sent, tt.rtts, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDiagnoseNotBlockedByLostEchoes checks that a device that doesn't answer
+// doesn't hold up the measurements of other devices
+func TestDiagnoseNotBlockedByLostEchoes(t *testing.T) {
+	silent := newFakeDevice(t, 1)
+	responsive := newFakeDevice(t, 2)
+	silent.handle(EchoRequest, nil)
+
+	c := newTestClient(t)
+	lost := silent.device(c)
+	answered := responsive.device(c)
+
+	done := make(chan PingStats)
+	go func() {
+		stats, err := lost.Diagnose(1, 0)
+		if err != nil {
+			t.Errorf("Diagnose: %v", err)
+		}
+		done <- stats
+	}()
+
+	// Wait until the echo to the silent device is in flight
+	for len(silent.requests()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	stats, err := answered.Diagnose(3, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= echoTimeout/2 {
+		t.Errorf("responsive device took %v while another echo was outstanding", elapsed)
+	}
+	if stats.Received != 3 {
+		t.Errorf("responsive device answered %d of 3 echoes", stats.Received)
+	}
+
+	if stats := <-done; stats.Loss != 100 {
+		t.Errorf("silent device has %.0f%% loss, want 100%%", stats.Loss)
+	}
+}
@@ -0,0 +1,117 @@
+package lifxlan
+
+import (
+	"errors"
+	"net"
+)
+
+// ErrTooManyRequests is returned when every sequence number is taken by a request awaiting a response
+var ErrTooManyRequests = errors.New("too many requests awaiting a response")
+
+// waiterQueue is how many responses can be queued for a request expecting
+// few replies before more are dropped
+const waiterQueue = 16
+
+// frame is a received datagram handed to the request it answers
+type frame struct {
+	buf  *[]byte // Pooled buffer holding data
+	data []byte
+	addr *net.UDPAddr
+}
+
+// register reserves a sequence number for a request and returns the channel
+// its responses are delivered on, which holds up to queue responses. Sequence
+// 0 is never handed out, so responses to packets sent without a registration
+// aren't mistaken for replies.
+func (c *Client) register(queue int) (uint8, chan frame, error) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	for i := 0; i < len(c.waiters); i++ {
+		c.nextSeq++
+		if c.nextSeq == 0 {
+			c.nextSeq++
+		}
+
+		if c.waiters[c.nextSeq] == nil {
+			frames := make(chan frame, queue)
+			c.waiters[c.nextSeq] = frames
+			return c.nextSeq, frames, nil
+		}
+	}
+
+	return 0, nil, ErrTooManyRequests
+}
+
+// unregister releases a sequence number and any responses still queued for it
+func (c *Client) unregister(seq uint8) {
+	c.wmu.Lock()
+	frames := c.waiters[seq]
+	c.waiters[seq] = nil
+	c.wmu.Unlock()
+
+	// Nothing is delivered once the waiter is removed, so the queue can be drained
+	for {
+		select {
+		case f := <-frames:
+			putBuffer(f.buf)
+		default:
+			return
+		}
+	}
+}
+
+// deliver copies a response to the request registered for its sequence number,
+// dropping it if nobody is waiting or the request's queue is full
+func (c *Client) deliver(h *Header, data []byte, addr *net.UDPAddr) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	frames := c.waiters[h.Sequence()]
+	if frames == nil {
+		return
+	}
+
+	buf := getBuffer()
+	f := frame{buf: buf, data: (*buf)[:copy(*buf, data)], addr: addr}
+
+	select {
+	case frames <- f:
+	default:
+		putBuffer(buf)
+	}
+}
+
+// readLoop is the only reader of the socket. It passes each datagram through
+// the inbound interceptors and hands responses to this client to the request
+// that sent them, until the connection is closed.
+func (c *Client) readLoop() {
+	defer close(c.readerDone)
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	for {
+		n, remote, err := c.conn.ReadFromUDP(*buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+
+		// Run inbound interceptors, skipping packets they drop
+		data, addr, err := c.intercept(Inbound, (*buf)[:n], remote)
+		if err != nil {
+			continue
+		}
+
+		// Ignore datagrams that aren't valid LIFX frames or aren't for this client
+		h, err := ParseHeader(data)
+		if err != nil || h.Source() != c.identifier {
+			continue
+		}
+
+		c.deliver(h, data, addr)
+	}
+}
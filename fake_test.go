@@ -0,0 +1,185 @@
+package lifxlan
+
+import (
+	"net"
+	"sync"
+	"testing"
+)
+
+// fakeDevice is a LIFX device on the loopback interface that answers requests
+// with canned replies. It listens on every local address so tests can reach it
+// through any loopback IP.
+type fakeDevice struct {
+	conn *net.UDPConn
+	mac  []byte
+
+	mu       sync.Mutex
+	handlers map[PacketType]func(payload []byte) []Message
+	received [][]byte // Every valid frame received, in order
+}
+
+// newFakeDevice starts a fake device that answers the queries made by
+// Discover and RefreshInfo. The last byte of its MAC address is id.
+func newFakeDevice(t *testing.T, id byte) *fakeDevice {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4zero})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fakeDevice{
+		conn:     conn,
+		mac:      []byte{0xd0, 0x73, 0xd5, 0x00, 0x00, id},
+		handlers: make(map[PacketType]func(payload []byte) []Message),
+	}
+
+	f.reply(GetService, &StateServiceMessage{Service: DeviceServiceUDP, Port: uint32(f.addr().Port)})
+	f.handle(EchoRequest, func(payload []byte) []Message {
+		var echo EchoResponseMessage
+		copy(echo.Echoing[:], payload)
+		return []Message{&echo}
+	})
+	f.reply(GetLabel, &StateLabelMessage{Label: labelBytes("Fake")})
+	f.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 27})
+	f.reply(GetHostFirmware, &StateHostFirmwareMessage{VersionMajor: 3, VersionMinor: 70})
+	f.reply(GetWifiFirmware, &StateWifiFirmwareMessage{VersionMajor: 3, VersionMinor: 70})
+	f.reply(GetPower, &StatePowerMessage{Level: 65535})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.serve()
+	}()
+
+	t.Cleanup(func() {
+		conn.Close()
+		<-done
+	})
+
+	return f
+}
+
+// addr returns the loopback address of the device
+func (f *fakeDevice) addr() *net.UDPAddr {
+	return &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: f.conn.LocalAddr().(*net.UDPAddr).Port}
+}
+
+// device adds the fake device to a client's registry
+func (f *fakeDevice) device(c *Client) *Device {
+	addr := f.addr()
+
+	device, _ := c.devices.add(NewDevice(f.mac, addr.IP, c))
+	device.update(func(info *DeviceInfo) {
+		info.Port = addr.Port
+	})
+
+	return device
+}
+
+// handle sets the function answering a packet type, a nil function leaves it unanswered
+func (f *fakeDevice) handle(t PacketType, fn func(payload []byte) []Message) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if fn == nil {
+		delete(f.handlers, t)
+		return
+	}
+	f.handlers[t] = fn
+}
+
+// reply answers a packet type with fixed messages
+func (f *fakeDevice) reply(t PacketType, msgs ...Message) {
+	f.handle(t, func([]byte) []Message {
+		return msgs
+	})
+}
+
+// requests returns a copy of every frame received so far
+func (f *fakeDevice) requests() [][]byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([][]byte(nil), f.received...)
+}
+
+// sentPayloads returns the payloads of the packets of type pt received by the
+// fake device, after a round trip that orders the check after earlier packets
+func sentPayloads(t *testing.T, f *fakeDevice, device *Device, pt PacketType) [][]byte {
+	t.Helper()
+
+	if _, err := device.GetLabel(); err != nil {
+		t.Fatal(err)
+	}
+
+	var payloads [][]byte
+	for _, frame := range f.requests() {
+		if h, _ := ParseHeader(frame); h.Type() == pt {
+			payloads = append(payloads, frame[HeaderSize:])
+		}
+	}
+
+	return payloads
+}
+
+// serve answers requests until the connection is closed
+func (f *fakeDevice) serve() {
+	buf := make([]byte, bufferSize)
+
+	for {
+		n, addr, err := f.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+
+		h, err := ParseHeader(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		f.mu.Lock()
+		f.received = append(f.received, append([]byte(nil), buf[:n]...))
+		handler := f.handlers[h.Type()]
+		f.mu.Unlock()
+
+		if handler == nil {
+			continue
+		}
+
+		// Responses echo the source and sequence of the request
+		for _, msg := range handler(buf[HeaderSize:n]) {
+			packet, err := AppendMessagePacket(nil, h.Source(), f.mac, msg)
+			if err != nil {
+				continue
+			}
+			(*Header)(packet).SetSequence(h.Sequence())
+
+			f.conn.WriteToUDP(packet, addr)
+		}
+	}
+}
+
+// newTestClient creates a client on an ephemeral loopback port that is closed when the test ends
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	c, err := newClient(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		c.Close()
+	})
+
+	return c
+}
+
+// useBroadcastAddress points discovery at addr for the rest of the test
+func useBroadcastAddress(t *testing.T, addr *net.UDPAddr) {
+	saved := BroadcastAddress
+	BroadcastAddress = *addr
+	t.Cleanup(func() {
+		BroadcastAddress = saved
+	})
+}
@@ -0,0 +1,48 @@
+package lifxlan
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported is returned when a device lacks the feature an operation needs
+var ErrUnsupported = errors.New("not supported by this device")
+
+// Features returns the features of the device, including those added by the
+// firmware it runs. The product and host firmware are queried if they aren't known yet.
+func (d *Device) Features() (Features, error) {
+	info := d.Info()
+
+	product := info.Product
+	if product.ProductID == 0 {
+		var err error
+		if product, err = d.GetProduct(); err != nil {
+			return Features{}, fmt.Errorf("failed to get product for device %s: %w", d.GetMACAddress(), err)
+		}
+	}
+
+	// The firmware only matters for products that gained features in an upgrade
+	firmware := info.HostFirmware
+	if len(product.Upgrades) > 0 && firmware.IsZero() {
+		var err error
+		if firmware, err = d.GetHostFirmware(); err != nil {
+			return Features{}, fmt.Errorf("failed to get host firmware for device %s: %w", d.GetMACAddress(), err)
+		}
+	}
+
+	return product.FeaturesAt(firmware), nil
+}
+
+// require returns an ErrUnsupported error unless the device has the named feature
+func (d *Device) require(feature string, has func(f Features) bool) error {
+	features, err := d.Features()
+	if err != nil {
+		return err
+	}
+
+	if !has(features) {
+		return fmt.Errorf("%w: %s on %s", ErrUnsupported, feature, d.GetMACAddress())
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package lifxlan
+
+import (
+	"cmp"
+	"fmt"
+	"time"
+)
+
+// FirmwareVersion identifies a firmware release of a device
+type FirmwareVersion struct {
+	Major uint16    `json:"major"`
+	Minor uint16    `json:"minor"`
+	Build time.Time `json:"build"` // When the firmware was built
+}
+
+// newFirmwareVersion converts the fields of a firmware state message
+func newFirmwareVersion(build uint64, major, minor uint16) FirmwareVersion {
+	v := FirmwareVersion{Major: major, Minor: minor}
+
+	// The build time is reported in nanoseconds since the epoch
+	if build != 0 {
+		v.Build = time.Unix(0, int64(build)).UTC()
+	}
+
+	return v
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is older than, the same
+// as, or newer than other. Only the major and minor versions are compared.
+func (v FirmwareVersion) Compare(other FirmwareVersion) int {
+	if c := cmp.Compare(v.Major, other.Major); c != 0 {
+		return c
+	}
+	return cmp.Compare(v.Minor, other.Minor)
+}
+
+// AtLeast reports whether v is the given version or newer
+func (v FirmwareVersion) AtLeast(major, minor uint16) bool {
+	return v.Compare(FirmwareVersion{Major: major, Minor: minor}) >= 0
+}
+
+// IsZero reports whether the version is unknown
+func (v FirmwareVersion) IsZero() bool {
+	return v.Major == 0 && v.Minor == 0 && v.Build.IsZero()
+}
+
+// String formats the version as major.minor
+func (v FirmwareVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// GetHostFirmware returns the version of the firmware running on the device
+func (d *Device) GetHostFirmware() (FirmwareVersion, error) {
+	var state StateHostFirmwareMessage
+	if err := d.request(&GetHostFirmwareMessage{}, &state); err != nil {
+		return FirmwareVersion{}, err
+	}
+
+	version := newFirmwareVersion(state.Build, state.VersionMajor, state.VersionMinor)
+
+	d.update(func(info *DeviceInfo) {
+		info.HostFirmware = version
+	})
+
+	return version, nil
+}
+
+// GetWifiFirmware returns the version of the firmware running on the Wi-Fi module
+func (d *Device) GetWifiFirmware() (FirmwareVersion, error) {
+	var state StateWifiFirmwareMessage
+	if err := d.request(&GetWifiFirmwareMessage{}, &state); err != nil {
+		return FirmwareVersion{}, err
+	}
+
+	version := newFirmwareVersion(state.Build, state.VersionMajor, state.VersionMinor)
+
+	d.update(func(info *DeviceInfo) {
+		info.WifiFirmware = version
+	})
+
+	return version, nil
+}
@@ -0,0 +1,103 @@
+package lifxlan
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetFirmware(t *testing.T) {
+	built := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetHostFirmware, &StateHostFirmwareMessage{Build: uint64(built.UnixNano()), VersionMajor: 3, VersionMinor: 90})
+	fake.reply(GetWifiFirmware, &StateWifiFirmwareMessage{VersionMajor: 1, VersionMinor: 2})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	host, err := device.GetHostFirmware()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (FirmwareVersion{Major: 3, Minor: 90, Build: built}); host != want {
+		t.Errorf("got host firmware %+v, want %+v", host, want)
+	}
+
+	// A zero build time means the device didn't report one
+	wifi, err := device.GetWifiFirmware()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (FirmwareVersion{Major: 1, Minor: 2}); wifi != want {
+		t.Errorf("got Wi-Fi firmware %+v, want %+v", wifi, want)
+	}
+
+	if info := device.Info(); info.HostFirmware != host || info.WifiFirmware != wifi {
+		t.Errorf("cached firmware %v and %v, want %v and %v", info.HostFirmware, info.WifiFirmware, host, wifi)
+	}
+}
+
+func TestFirmwareVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b FirmwareVersion
+		want int
+	}{
+		{FirmwareVersion{Major: 2, Minor: 77}, FirmwareVersion{Major: 2, Minor: 77}, 0},
+		{FirmwareVersion{Major: 2, Minor: 76}, FirmwareVersion{Major: 2, Minor: 77}, -1},
+		{FirmwareVersion{Major: 3, Minor: 0}, FirmwareVersion{Major: 2, Minor: 80}, 1},
+		{FirmwareVersion{Major: 2, Minor: 77, Build: time.Now()}, FirmwareVersion{Major: 2, Minor: 77}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Compare(tt.b); got != tt.want {
+			t.Errorf("%v.Compare(%v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFeaturesAt(t *testing.T) {
+	product, err := GetProduct(1, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		firmware FirmwareVersion
+		extended bool
+		kelvin   [2]int
+	}{
+		{FirmwareVersion{}, false, [2]int{2500, 9000}},
+		{FirmwareVersion{Major: 2, Minor: 76}, false, [2]int{2500, 9000}},
+		{FirmwareVersion{Major: 2, Minor: 77}, true, [2]int{2500, 9000}},
+		{FirmwareVersion{Major: 2, Minor: 80}, true, [2]int{1500, 9000}},
+		{FirmwareVersion{Major: 3, Minor: 0}, true, [2]int{1500, 9000}},
+	}
+
+	for _, tt := range tests {
+		features := product.FeaturesAt(tt.firmware)
+		if features.ExtendedMultizone != tt.extended || features.TemperatureRange != tt.kelvin {
+			t.Errorf("at %v: extended multizone %v and range %v, want %v and %v",
+				tt.firmware, features.ExtendedMultizone, features.TemperatureRange, tt.extended, tt.kelvin)
+		}
+		if !features.Multizone {
+			t.Errorf("at %v: upgrades removed multizone", tt.firmware)
+		}
+	}
+}
+
+func TestFirmwareGatesFeatures(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 32})
+	fake.reply(GetHostFirmware, &StateHostFirmwareMessage{VersionMajor: 2, VersionMinor: 76})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	if _, err := device.GetExtendedColorZones(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetExtendedColorZones before the upgrade returned %v, want ErrUnsupported", err)
+	}
+	if n := len(sentPayloads(t, fake, device, GetExtendedColorZones)); n != 0 {
+		t.Errorf("sent %d GetExtendedColorZones packets before the upgrade", n)
+	}
+}
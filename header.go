@@ -3,26 +3,64 @@ package lifxlan
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"net"
+)
+
+var (
+	// ErrShortHeader is returned when a datagram is too small to hold a header
+	ErrShortHeader = errors.New("insufficient data to unpack header")
+
+	// ErrSizeMismatch is returned when the header size doesn't match the datagram length
+	ErrSizeMismatch = errors.New("header size does not match datagram length")
+
+	// ErrUnsupportedProtocol is returned when the protocol number isn't 1024
+	ErrUnsupportedProtocol = errors.New("unsupported protocol number")
+
+	// ErrNotAddressable is returned when the addressable bit isn't set
+	ErrNotAddressable = errors.New("addressable bit not set")
 )
 
 // Header represents a 36-byte LIFX protocol header (https://lan.developer.lifx.com/docs/packet-contents#header)
 type Header [HeaderSize]byte
 
-// ParseHeader parses the first 36 bytes into a Header struct
+// ParseHeader parses the header of a datagram, rejecting anything that isn't a valid LIFX frame
 func ParseHeader(data []byte) (*Header, error) {
 	// Check if the data length is less than the header size
 	if len(data) < HeaderSize {
 		// Return an error indicating insufficient data
-		return nil, errors.New("insufficient data to unpack header")
+		return nil, fmt.Errorf("%w: got %d bytes", ErrShortHeader, len(data))
 	}
 	// Create a new Header instance and copy the first 36 bytes into it
 	var h Header
 	copy(h[:], data[:HeaderSize])
 
+	if err := h.Validate(len(data)); err != nil {
+		return nil, err
+	}
+
 	// Return the pointer to the Header instance and nil error
 	return &h, nil
 }
 
+// Validate checks the header of a datagram of the given length
+func (h *Header) Validate(length int) error {
+	// Truncated or padded datagrams don't match their declared size
+	if int(h.Size()) != length {
+		return fmt.Errorf("%w: header says %d bytes, got %d", ErrSizeMismatch, h.Size(), length)
+	}
+
+	if h.Protocol() != Protocol {
+		return fmt.Errorf("%w: %d", ErrUnsupportedProtocol, h.Protocol())
+	}
+
+	if !h.Addressable() {
+		return ErrNotAddressable
+	}
+
+	return nil
+}
+
 // Size returns the total message size
 func (h *Header) Size() uint16 {
 	// Read the first 2 bytes as a little-endian uint16
@@ -49,6 +87,12 @@ func (h *Header) Tagged() bool {
 	return h[3]&0x20 != 0
 }
 
+// Origin returns the two origin bits, which are always zero
+func (h *Header) Origin() uint8 {
+	// Read the top 2 bits of byte 3
+	return h[3] >> 6
+}
+
 // Source returns the client source identifier
 func (h *Header) Source() uint32 {
 	// Read bytes 4-7 as a little-endian uint32
@@ -63,22 +107,22 @@ func (h *Header) Target() []byte {
 	return mac
 }
 
-// ResponseRequired returns true if the response required bit (bit 0 of byte 15) is set
+// ResponseRequired returns true if the response required bit (bit 0 of byte 22) is set
 func (h *Header) ResponseRequired() bool {
-	// Check if bit 0 of byte 15 is set
-	return h[15]&0x01 != 0
+	// Check if bit 0 of byte 22 is set
+	return h[22]&0x01 != 0
 }
 
-// AckRequired returns true if the ack required bit (bit 1 of byte 15) is set
+// AckRequired returns true if the ack required bit (bit 1 of byte 22) is set
 func (h *Header) AckRequired() bool {
-	// Check if bit 1 of byte 15 is set
-	return h[15]&0x02 != 0
+	// Check if bit 1 of byte 22 is set
+	return h[22]&0x02 != 0
 }
 
 // Sequence returns the sequence number
 func (h *Header) Sequence() uint8 {
-	// Shift byte 15 to the right by 2 bits and return it
-	return h[15] >> 2
+	// The sequence number is byte 23
+	return h[23]
 }
 
 // Type returns the payload type
@@ -91,6 +135,15 @@ func NewHeader(source uint32) *Header {
 	// Create a new Header instance
 	h := &Header{}
 
+	// Set the protocol number and default values for the header
+	h.init(source)
+
+	// Return the pointer to the new Header instance
+	return h
+}
+
+// init sets the protocol number, addressable bit and source of a zeroed header
+func (h *Header) init(source uint32) {
 	// Set the protocol number to the LIFX protocol
 	protoField := uint16(Protocol)
 	binary.LittleEndian.PutUint16(h[2:4], protoField)
@@ -98,9 +151,6 @@ func NewHeader(source uint32) *Header {
 	// Set default values for the header
 	h.SetAddressable(true)
 	h.SetSource(source)
-
-	// Return the pointer to the new Header instance
-	return h
 }
 
 // SetSize sets the total message size
@@ -147,30 +197,30 @@ func (h *Header) SetTarget(mac []byte) {
 
 // SetResponseRequired sets the response required bit to a boolean value
 func (h *Header) SetResponseRequired(responseRequired bool) {
-	// If responseRequired is true, set bit 0 of byte 15
+	// If responseRequired is true, set bit 0 of byte 22
 	if responseRequired {
-		h[15] |= 0x01
+		h[22] |= 0x01
 	} else {
-		// Otherwise, clear bit 0 of byte 15
-		h[15] &^= 0x01
+		// Otherwise, clear bit 0 of byte 22
+		h[22] &^= 0x01
 	}
 }
 
 // SetAckRequired sets the ack required bit to a boolean value
 func (h *Header) SetAckRequired(ackRequired bool) {
-	// If ackRequired is true, set bit 1 of byte 15
+	// If ackRequired is true, set bit 1 of byte 22
 	if ackRequired {
-		h[15] |= 0x02
+		h[22] |= 0x02
 	} else {
-		// Otherwise, clear bit 1 of byte 15
-		h[15] &^= 0x02
+		// Otherwise, clear bit 1 of byte 22
+		h[22] &^= 0x02
 	}
 }
 
 // SetSequence sets the sequence number
 func (h *Header) SetSequence(sequence uint8) {
-	// Shift the sequence number to the left by 2 bits and set it in byte 15
-	h[15] = (h[15] & 0x03) | (sequence << 2)
+	// Set the sequence number in byte 23
+	h[23] = sequence
 }
 
 // SetType sets the packet type
@@ -197,3 +247,60 @@ func DefaultHeader(source uint32, target []byte, packetType PacketType, payloadS
 
 	return header
 }
+
+// AppendHeader appends a default header to dst, allocating only if dst lacks capacity
+func AppendHeader(dst []byte, source uint32, target []byte, packetType PacketType, payloadSize uint16) []byte {
+	dst, b := grow(dst, HeaderSize)
+
+	// Write the header fields directly into the appended bytes
+	h := (*Header)(b)
+	h.init(source)
+	h.SetSize(uint16(HeaderSize + payloadSize))
+	h.SetType(packetType)
+	h.SetTarget(target)
+
+	return dst
+}
+
+// FrameInfo is a decoded view of every header field
+type FrameInfo struct {
+	Size             uint16
+	Protocol         uint16
+	Addressable      bool
+	Tagged           bool
+	Origin           uint8
+	Source           uint32
+	Target           net.HardwareAddr
+	ResponseRequired bool
+	AckRequired      bool
+	Sequence         uint8
+	Type             PacketType
+}
+
+// Info decodes the header fields
+func (h *Header) Info() FrameInfo {
+	return FrameInfo{
+		Size:             h.Size(),
+		Protocol:         h.Protocol(),
+		Addressable:      h.Addressable(),
+		Tagged:           h.Tagged(),
+		Origin:           h.Origin(),
+		Source:           h.Source(),
+		Target:           net.HardwareAddr(append([]byte(nil), h.Target()...)),
+		ResponseRequired: h.ResponseRequired(),
+		AckRequired:      h.AckRequired(),
+		Sequence:         h.Sequence(),
+		Type:             h.Type(),
+	}
+}
+
+// String formats the header fields for logging
+func (h *Header) String() string {
+	return h.Info().String()
+}
+
+// String formats the frame as the packet type name followed by every header field
+func (f FrameInfo) String() string {
+	return fmt.Sprintf("%s(%d) size=%d protocol=%d addressable=%t tagged=%t origin=%d source=0x%08x target=%s res_required=%t ack_required=%t sequence=%d",
+		f.Type, uint16(f.Type), f.Size, f.Protocol, f.Addressable, f.Tagged, f.Origin, f.Source, f.Target, f.ResponseRequired, f.AckRequired, f.Sequence)
+}
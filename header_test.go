@@ -0,0 +1,101 @@
+package lifxlan
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestHeaderGolden checks the header layout against the SetColor example in
+// https://lan.developer.lifx.com/docs/packet-contents
+func TestHeaderGolden(t *testing.T) {
+	h := DefaultHeader(0, zeroTarget[:], SetColor, 13)
+	h.SetTagged(true)
+
+	want := unhex(t, `
+		31 00 00 34 00 00 00 00
+		00 00 00 00 00 00 00 00
+		00 00 00 00 00 00 00 00
+		00 00 00 00 00 00 00 00
+		66 00 00 00`)
+	if !bytes.Equal(h[:], want) {
+		t.Errorf("got  % x\nwant % x", h[:], want)
+	}
+
+	// The flags and sequence follow the reserved bytes of the frame address
+	h.SetResponseRequired(true)
+	h.SetAckRequired(true)
+	h.SetSequence(42)
+	if h[22] != 0x03 || h[23] != 42 {
+		t.Errorf("got flags 0x%02x sequence %d, want 0x03 and 42", h[22], h[23])
+	}
+	if !h.ResponseRequired() || !h.AckRequired() || h.Sequence() != 42 {
+		t.Errorf("got %s", h)
+	}
+}
+
+func TestFrameInfoString(t *testing.T) {
+	h := DefaultHeader(0x42, benchTarget, GetLabel, 0)
+	h.SetSequence(7)
+
+	want := "GetLabel(23) size=36 protocol=1024 addressable=true tagged=false origin=0 source=0x00000042 target=d0:73:d5:01:02:03 res_required=false ack_required=false sequence=7"
+	if got := h.Info().String(); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestParseHeaderRejectsInvalidFrames(t *testing.T) {
+	valid := BuildSetColorPacket(1, benchTarget, NewColor(1, 2, 3, 4), time.Second)
+
+	foreign := append([]byte(nil), valid...)
+	foreign[2] = 0x01 // Protocol 1025
+
+	unaddressable := append([]byte(nil), valid...)
+	(*Header)(unaddressable).SetAddressable(false)
+
+	tests := map[string]struct {
+		data []byte
+		want error
+	}{
+		"short":         {valid[:HeaderSize-1], ErrShortHeader},
+		"truncated":     {valid[:len(valid)-1], ErrSizeMismatch},
+		"padded":        {append(valid[:len(valid):len(valid)], 0), ErrSizeMismatch},
+		"protocol":      {foreign, ErrUnsupportedProtocol},
+		"unaddressable": {unaddressable, ErrNotAddressable},
+	}
+
+	for name, tt := range tests {
+		if _, err := ParseHeader(tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", name, err, tt.want)
+		}
+	}
+
+	if _, err := ParseHeader(valid); err != nil {
+		t.Errorf("valid frame: %v", err)
+	}
+}
+
+func FuzzParseHeader(f *testing.F) {
+	f.Add([]byte{})
+	f.Add(BuildDiscoveryPacket(1))
+	f.Add(BuildSetColorPacket(1, benchTarget, NewColor(21845, 65535, 65535, 3500), time.Second))
+	f.Add(BuildEchoRequestPacket(1, benchTarget, make([]byte, 64)))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		h, err := ParseHeader(data)
+		if err != nil {
+			return
+		}
+
+		// Accepted headers describe the whole datagram
+		if int(h.Size()) != len(data) || h.Protocol() != Protocol || !h.Addressable() {
+			t.Fatalf("accepted invalid header %s for %d bytes", h, len(data))
+		}
+		if !bytes.Equal(h[:], data[:HeaderSize]) {
+			t.Fatalf("header doesn't match datagram")
+		}
+
+		_ = h.String()
+	})
+}
@@ -0,0 +1,111 @@
+package lifxlan
+
+import "time"
+
+// HevCycle is the state of a HEV cleaning cycle on a LIFX Clean
+type HevCycle struct {
+	Duration  time.Duration // Length of the running cycle
+	Remaining time.Duration // Time left in the running cycle, zero when the light isn't in HEV mode
+	LastPower bool          // Whether the light was on before the cycle started
+}
+
+// Active reports whether the light is in HEV mode
+func (c HevCycle) Active() bool {
+	return c.Remaining > 0
+}
+
+// HevCycleConfiguration holds the defaults used for HEV cycles
+type HevCycleConfiguration struct {
+	Indication bool          // Briefly flash the light when a cycle finishes
+	Duration   time.Duration // Duration of cycles started without one
+}
+
+// hasHev reports whether a product has HEV LEDs
+func hasHev(f Features) bool { return f.Hev }
+
+// seconds converts a duration to whole seconds for the HEV messages
+func seconds(d time.Duration) uint32 {
+	return uint32(d.Round(time.Second) / time.Second)
+}
+
+// StartHevCycle starts a HEV cycle, a zero duration uses the configured default
+func (d *Device) StartHevCycle(duration time.Duration) error {
+	if err := d.require("hev", hasHev); err != nil {
+		return err
+	}
+
+	return d.send(&SetHevCycleMessage{
+		Enable:    true,
+		DurationS: seconds(duration),
+	})
+}
+
+// StopHevCycle stops the running HEV cycle
+func (d *Device) StopHevCycle() error {
+	if err := d.require("hev", hasHev); err != nil {
+		return err
+	}
+
+	return d.send(&SetHevCycleMessage{Enable: false})
+}
+
+// GetHevCycle returns the state of the current HEV cycle
+func (d *Device) GetHevCycle() (HevCycle, error) {
+	if err := d.require("hev", hasHev); err != nil {
+		return HevCycle{}, err
+	}
+
+	var state StateHevCycleMessage
+	if err := d.request(&GetHevCycleMessage{}, &state); err != nil {
+		return HevCycle{}, err
+	}
+
+	return HevCycle{
+		Duration:  time.Duration(state.DurationS) * time.Second,
+		Remaining: time.Duration(state.RemainingS) * time.Second,
+		LastPower: state.LastPower,
+	}, nil
+}
+
+// GetHevCycleConfiguration returns the default HEV cycle duration and indication
+func (d *Device) GetHevCycleConfiguration() (HevCycleConfiguration, error) {
+	if err := d.require("hev", hasHev); err != nil {
+		return HevCycleConfiguration{}, err
+	}
+
+	var state StateHevCycleConfigurationMessage
+	if err := d.request(&GetHevCycleConfigurationMessage{}, &state); err != nil {
+		return HevCycleConfiguration{}, err
+	}
+
+	return HevCycleConfiguration{
+		Indication: state.Indication,
+		Duration:   time.Duration(state.DurationS) * time.Second,
+	}, nil
+}
+
+// SetHevCycleConfiguration sets the default HEV cycle duration and indication
+func (d *Device) SetHevCycleConfiguration(config HevCycleConfiguration) error {
+	if err := d.require("hev", hasHev); err != nil {
+		return err
+	}
+
+	return d.send(&SetHevCycleConfigurationMessage{
+		Indication: config.Indication,
+		DurationS:  seconds(config.Duration),
+	})
+}
+
+// GetLastHevCycleResult returns how the most recent HEV cycle ended
+func (d *Device) GetLastHevCycleResult() (LightLastHevCycleResult, error) {
+	if err := d.require("hev", hasHev); err != nil {
+		return LightLastHevCycleResultNone, err
+	}
+
+	var state StateLastHevCycleResultMessage
+	if err := d.request(&GetLastHevCycleResultMessage{}, &state); err != nil {
+		return LightLastHevCycleResultNone, err
+	}
+
+	return state.Result, nil
+}
@@ -0,0 +1,110 @@
+package lifxlan
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// newFakeClean returns a fake LIFX Clean with a HEV cycle in progress
+func newFakeClean(t *testing.T) (*fakeDevice, *Device) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 90})
+	fake.reply(GetHevCycle, &StateHevCycleMessage{DurationS: 7200, RemainingS: 1800, LastPower: true})
+	fake.reply(GetHevCycleConfiguration, &StateHevCycleConfigurationMessage{Indication: true, DurationS: 3600})
+	fake.reply(GetLastHevCycleResult, &StateLastHevCycleResultMessage{Result: LightLastHevCycleResultInterruptedByLan})
+
+	c := newTestClient(t)
+	return fake, fake.device(c)
+}
+
+func TestHevCycle(t *testing.T) {
+	fake, device := newFakeClean(t)
+
+	// Durations are sent in whole seconds, rounded to the nearest
+	if err := device.StartHevCycle(90*time.Minute + 400*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := device.StopHevCycle(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []SetHevCycleMessage{
+		{Enable: true, DurationS: 5400},
+		{Enable: false},
+	}
+	payloads := sentPayloads(t, fake, device, SetHevCycle)
+	if len(payloads) != len(want) {
+		t.Fatalf("sent %d SetHevCycle packets, want %d", len(payloads), len(want))
+	}
+	for i, payload := range payloads {
+		var msg SetHevCycleMessage
+		if err := msg.UnmarshalBinary(payload); err != nil {
+			t.Fatal(err)
+		}
+		if msg != want[i] {
+			t.Errorf("packet %d is %+v, want %+v", i, msg, want[i])
+		}
+	}
+
+	cycle, err := device.GetHevCycle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCycle := HevCycle{Duration: 2 * time.Hour, Remaining: 30 * time.Minute, LastPower: true}
+	if cycle != wantCycle || !cycle.Active() {
+		t.Errorf("got cycle %+v, want an active %+v", cycle, wantCycle)
+	}
+
+	result, err := device.GetLastHevCycleResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != LightLastHevCycleResultInterruptedByLan {
+		t.Errorf("got last result %v, want %v", result, LightLastHevCycleResultInterruptedByLan)
+	}
+}
+
+func TestHevCycleConfiguration(t *testing.T) {
+	fake, device := newFakeClean(t)
+
+	config, err := device.GetHevCycleConfiguration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (HevCycleConfiguration{Indication: true, Duration: time.Hour}); config != want {
+		t.Errorf("got configuration %+v, want %+v", config, want)
+	}
+
+	if err := device.SetHevCycleConfiguration(HevCycleConfiguration{Duration: 45 * time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+
+	payloads := sentPayloads(t, fake, device, SetHevCycleConfiguration)
+	if len(payloads) != 1 {
+		t.Fatalf("sent %d SetHevCycleConfiguration packets, want 1", len(payloads))
+	}
+	var msg SetHevCycleConfigurationMessage
+	if err := msg.UnmarshalBinary(payloads[0]); err != nil {
+		t.Fatal(err)
+	}
+	if want := (SetHevCycleConfigurationMessage{DurationS: 2700}); msg != want {
+		t.Errorf("sent %+v, want %+v", msg, want)
+	}
+}
+
+func TestHevUnsupported(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	if err := device.StartHevCycle(time.Hour); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("StartHevCycle returned %v, want ErrUnsupported", err)
+	}
+	if _, err := device.GetHevCycle(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetHevCycle returned %v, want ErrUnsupported", err)
+	}
+	if n := len(sentPayloads(t, fake, device, SetHevCycle)); n != 0 {
+		t.Errorf("sent %d SetHevCycle packets to a device without HEV", n)
+	}
+}
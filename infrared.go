@@ -0,0 +1,33 @@
+package lifxlan
+
+import "math"
+
+// hasInfrared reports whether a product has an infrared LED
+func hasInfrared(f Features) bool { return f.Infrared }
+
+// GetInfrared returns the brightness of the infrared LED from 0 to 1
+func (d *Device) GetInfrared() (float64, error) {
+	if err := d.require("infrared", hasInfrared); err != nil {
+		return 0, err
+	}
+
+	var state StateInfraredMessage
+	if err := d.request(&GetInfraredMessage{}, &state); err != nil {
+		return 0, err
+	}
+
+	return float64(state.Brightness) / 0xFFFF, nil
+}
+
+// SetInfrared sets the brightness of the infrared LED from 0 to 1
+func (d *Device) SetInfrared(level float64) error {
+	if err := d.require("infrared", hasInfrared); err != nil {
+		return err
+	}
+
+	level = math.Max(0, math.Min(1, level))
+
+	return d.send(&SetInfraredMessage{
+		Brightness: uint16(math.Round(0xFFFF * level)),
+	})
+}
@@ -0,0 +1,62 @@
+package lifxlan
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInfrared(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 29})
+	fake.reply(GetInfrared, &StateInfraredMessage{Brightness: 0xFFFF})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	level, err := device.GetInfrared()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level != 1 {
+		t.Errorf("got infrared level %v, want 1", level)
+	}
+
+	for _, level := range []float64{0.5, 2} {
+		if err := device.SetInfrared(level); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Levels are scaled to the full range and clamped to 1
+	want := []uint16{32768, 0xFFFF}
+	payloads := sentPayloads(t, fake, device, SetInfrared)
+	if len(payloads) != len(want) {
+		t.Fatalf("sent %d SetInfrared packets, want %d", len(payloads), len(want))
+	}
+	for i, payload := range payloads {
+		var msg SetInfraredMessage
+		if err := msg.UnmarshalBinary(payload); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Brightness != want[i] {
+			t.Errorf("packet %d sets brightness %d, want %d", i, msg.Brightness, want[i])
+		}
+	}
+}
+
+func TestInfraredUnsupported(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	if _, err := device.GetInfrared(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetInfrared returned %v, want ErrUnsupported", err)
+	}
+	if err := device.SetInfrared(1); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetInfrared returned %v, want ErrUnsupported", err)
+	}
+
+	if n := len(sentPayloads(t, fake, device, GetInfrared)) + len(sentPayloads(t, fake, device, SetInfrared)); n != 0 {
+		t.Errorf("sent %d infrared packets to a device without infrared", n)
+	}
+}
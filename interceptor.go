@@ -0,0 +1,115 @@
+package lifxlan
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Direction indicates whether an intercepted packet is leaving or arriving at the client
+type Direction int
+
+const (
+	Outbound Direction = iota // Packet is about to be written to the network
+	Inbound                   // Packet was read from the network
+)
+
+// String returns the name of the direction
+func (d Direction) String() string {
+	switch d {
+	case Outbound:
+		return "outbound"
+	case Inbound:
+		return "inbound"
+	default:
+		return fmt.Sprintf("Direction(%d)", int(d))
+	}
+}
+
+// ErrDropPacket can be returned by an Interceptor to discard a packet
+var ErrDropPacket = errors.New("packet dropped by interceptor")
+
+// Packet is the decoded view of a datagram passed through the interceptor chain
+type Packet struct {
+	Direction Direction
+	Header    Header       // Decoded header, changes are written back to the datagram
+	Payload   []byte       // Bytes following the header
+	Addr      *net.UDPAddr // Destination for outbound packets, sender for inbound packets
+}
+
+// Interceptor observes, modifies, delays or drops a packet.
+//
+// Interceptors run in the order they were registered and may block to delay a
+// packet. Returning an error stops the chain: an outbound packet is not sent and
+// the error is returned to the caller, an inbound packet is discarded. The header
+// size field is recomputed from the payload once the chain completes.
+type Interceptor func(p *Packet) error
+
+// Use appends interceptors to the end of the client's chain
+func (c *Client) Use(interceptors ...Interceptor) {
+	c.imu.Lock()
+	defer c.imu.Unlock()
+
+	// Copy on write so running chains keep a consistent view
+	chain := make([]Interceptor, 0, len(c.interceptors)+len(interceptors))
+	chain = append(chain, c.interceptors...)
+	chain = append(chain, interceptors...)
+	c.interceptors = chain
+}
+
+// intercept runs a datagram through the interceptor chain and returns the
+// resulting datagram and address
+func (c *Client) intercept(dir Direction, data []byte, addr *net.UDPAddr) ([]byte, *net.UDPAddr, error) {
+	c.imu.RLock()
+	chain := c.interceptors
+	c.imu.RUnlock()
+
+	// Nothing to do without interceptors
+	if len(chain) == 0 {
+		return data, addr, nil
+	}
+
+	h, err := ParseHeader(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Copy the payload and address so interceptors can't modify the caller's values
+	p := &Packet{
+		Direction: dir,
+		Header:    *h,
+		Payload:   append([]byte(nil), data[HeaderSize:]...),
+	}
+	if addr != nil {
+		a := *addr
+		p.Addr = &a
+	}
+
+	for _, interceptor := range chain {
+		if err := interceptor(p); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Reassemble the datagram from the possibly modified header and payload
+	p.Header.SetSize(uint16(HeaderSize + len(p.Payload)))
+	out := make([]byte, HeaderSize+len(p.Payload))
+	copy(out, p.Header[:])
+	copy(out[HeaderSize:], p.Payload)
+
+	return out, p.Addr, nil
+}
+
+// Message decodes the payload into a typed message
+func (p *Packet) Message() (Message, error) {
+	msg, err := NewMessage(p.Header.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := msg.UnmarshalBinary(p.Payload); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
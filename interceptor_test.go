@@ -0,0 +1,204 @@
+package lifxlan
+
+import (
+	"errors"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInterceptorChainOrder(t *testing.T) {
+	c := &Client{}
+
+	var order []string
+	record := func(name string) Interceptor {
+		return func(p *Packet) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	c.Use(record("first"), record("second"))
+	c.Use(record("third"))
+
+	packet := BuildDiscoveryPacket(1)
+	if _, _, err := c.intercept(Outbound, packet, &BroadcastAddress); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"first", "second", "third"}; !slices.Equal(order, want) {
+		t.Errorf("interceptors ran in order %v, want %v", order, want)
+	}
+}
+
+func TestInterceptorErrorStopsChain(t *testing.T) {
+	c := &Client{}
+
+	ran := false
+	c.Use(
+		func(p *Packet) error { return ErrDropPacket },
+		func(p *Packet) error { ran = true; return nil },
+	)
+
+	_, _, err := c.intercept(Outbound, BuildDiscoveryPacket(1), &BroadcastAddress)
+	if !errors.Is(err, ErrDropPacket) {
+		t.Errorf("intercept returned %v, want ErrDropPacket", err)
+	}
+	if ran {
+		t.Error("interceptor after the failing one ran")
+	}
+}
+
+func TestInterceptorRewriteRecomputesSize(t *testing.T) {
+	c := &Client{}
+
+	c.Use(func(p *Packet) error {
+		p.Header.SetType(EchoRequest)
+		p.Payload = append(p.Payload, make([]byte, 64)...)
+		return nil
+	})
+
+	packet := BuildDiscoveryPacket(1)
+	original := slices.Clone(packet)
+
+	out, _, err := c.intercept(Outbound, packet, &BroadcastAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := ParseHeader(out)
+	if err != nil {
+		t.Fatalf("rewritten packet is invalid: %v", err)
+	}
+	if got, want := int(h.Size()), HeaderSize+64; got != want || len(out) != want {
+		t.Errorf("size field %d and length %d, want %d", got, len(out), want)
+	}
+	if h.Type() != EchoRequest {
+		t.Errorf("type %v, want %v", h.Type(), EchoRequest)
+	}
+
+	if !slices.Equal(packet, original) {
+		t.Error("interceptor modified the caller's packet")
+	}
+}
+
+func TestUseIsCopyOnWrite(t *testing.T) {
+	c := &Client{}
+
+	var mu sync.Mutex
+	var calls []string
+	record := func(name string) Interceptor {
+		return func(p *Packet) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	// An interceptor registered while the chain runs only sees later packets
+	added := false
+	c.Use(func(p *Packet) error {
+		if !added {
+			added = true
+			c.Use(record("late"))
+		}
+		return record("early")(p)
+	})
+
+	packet := BuildDiscoveryPacket(1)
+	for i := 0; i < 2; i++ {
+		if _, _, err := c.intercept(Outbound, packet, &BroadcastAddress); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if want := []string{"early", "early", "late"}; !slices.Equal(calls, want) {
+		t.Errorf("interceptors ran %v, want %v", calls, want)
+	}
+}
+
+func TestInterceptorDropsOutboundPacket(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	c.Use(func(p *Packet) error {
+		if p.Direction == Outbound && p.Header.Type() == SetColor {
+			return ErrDropPacket
+		}
+		return nil
+	})
+
+	if err := device.SetColor(NewColor(0, 0, 65535, 3500), 0); !errors.Is(err, ErrDropPacket) {
+		t.Fatalf("SetColor returned %v, want ErrDropPacket", err)
+	}
+
+	// The label round trip orders the check after anything SetColor might have sent
+	if _, err := device.GetLabel(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, frame := range fake.requests() {
+		if h, _ := ParseHeader(frame); h.Type() == SetColor {
+			t.Error("dropped SetColor reached the device")
+		}
+	}
+}
+
+func TestInterceptorDropsInboundPacket(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	fake.reply(GetLabel,
+		&StateLabelMessage{Label: labelBytes("dropped")},
+		&StateLabelMessage{Label: labelBytes("kept")},
+	)
+
+	dropped := false
+	c.Use(func(p *Packet) error {
+		if p.Direction == Inbound && p.Header.Type() == StateLabel && !dropped {
+			dropped = true
+			return ErrDropPacket
+		}
+		return nil
+	})
+
+	label, err := device.GetLabel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label != "kept" {
+		t.Errorf("label %q, want the reply after the dropped one", label)
+	}
+}
+
+func TestDiscoverUsesInterceptedAddress(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	useBroadcastAddress(t, fake.addr())
+
+	// Pretend the device answered from another address on the loopback network
+	rewritten := net.IPv4(127, 0, 0, 2)
+	c.Use(func(p *Packet) error {
+		if p.Direction == Inbound && p.Header.Type() == StateService {
+			p.Addr.IP = rewritten
+		}
+		return nil
+	})
+
+	if err := c.Discover(200 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	devices := c.GetDevices()
+	if len(devices) != 1 {
+		t.Fatalf("discovered %d devices, want 1", len(devices))
+	}
+	if ip := devices[0].Info().IP; !ip.Equal(rewritten) {
+		t.Errorf("device recorded at %v, want the intercepted address %v", ip, rewritten)
+	}
+}
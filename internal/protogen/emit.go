@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// emitter accumulates Go source
+type emitter struct {
+	bytes.Buffer
+}
+
+func (e *emitter) p(format string, args ...any) {
+	fmt.Fprintf(e, format, args...)
+	e.WriteByte('\n')
+}
+
+// source returns the formatted source
+func (e *emitter) source() ([]byte, error) {
+	src, err := format.Source(e.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, e.Bytes())
+	}
+	return src, nil
+}
+
+// emitPacketTypes generates the PacketType constants and their names
+func emitPacketTypes(proto *protocol, header string) ([]byte, error) {
+	e := &emitter{}
+	e.p("%s", header)
+	e.p("package lifxlan")
+	e.p("")
+	e.p("import \"fmt\"")
+	e.p("")
+
+	// Group constants by namespace in the order they first appear
+	var namespaces []string
+	byNamespace := map[string][]*packet{}
+	for _, pkt := range proto.packets {
+		if _, ok := byNamespace[pkt.namespace]; !ok {
+			namespaces = append(namespaces, pkt.namespace)
+		}
+		byNamespace[pkt.namespace] = append(byNamespace[pkt.namespace], pkt)
+	}
+
+	for _, ns := range namespaces {
+		e.p("// %s messages", namespaceTitle(ns))
+		e.p("")
+		e.p("const (")
+		for _, pkt := range byNamespace[ns] {
+			e.p("%s PacketType = %d // %s", pkt.constName, pkt.pktType, pkt.name)
+		}
+		e.p(")")
+		e.p("")
+	}
+
+	e.p("// String returns the name of the packet type")
+	e.p("func (t PacketType) String() string {")
+	e.p("switch t {")
+	for _, pkt := range proto.packets {
+		e.p("case %s:", pkt.constName)
+		e.p("return %q", pkt.constName)
+	}
+	e.p("}")
+	e.p("return fmt.Sprintf(\"PacketType(%%d)\", uint16(t))")
+	e.p("}")
+
+	return e.source()
+}
+
+// emitMessages generates the enums, field structures and message types
+func emitMessages(proto *protocol, header string) ([]byte, error) {
+	e := &emitter{}
+	e.p("%s", header)
+	e.p("package lifxlan")
+	e.p("")
+	e.p("import (")
+	e.p("\"encoding/binary\"")
+	e.p("\"fmt\"")
+	e.p("\"math\"")
+	e.p(")")
+	e.p("")
+
+	for _, en := range proto.enums {
+		emitEnum(e, en)
+	}
+	for _, s := range proto.structs {
+		emitStruct(e, s)
+	}
+	for _, pkt := range proto.packets {
+		emitMessage(e, pkt)
+	}
+
+	e.p("// messageTypes creates an empty message for each packet type, see RegisterMessage")
+	e.p("var messageTypes = map[PacketType]func() Message{")
+	for _, pkt := range proto.packets {
+		e.p("%s: func() Message { return new(%s) },", pkt.constName, messageName(pkt))
+	}
+	e.p("}")
+
+	return e.source()
+}
+
+func emitEnum(e *emitter, en *enum) {
+	e.p("// %s is the %s enum of the protocol definition", en.name, en.name)
+	e.p("type %s %s", en.name, en.base)
+	e.p("")
+	e.p("const (")
+	for _, v := range en.values {
+		e.p("%s %s = %d", v.goName, en.name, v.value)
+	}
+	e.p(")")
+	e.p("")
+	e.p("// String returns the definition name of the value")
+	e.p("func (v %s) String() string {", en.name)
+	e.p("switch v {")
+	for _, v := range en.values {
+		e.p("case %s:", v.goName)
+		e.p("return %q", v.shortName)
+	}
+	e.p("}")
+	e.p("return fmt.Sprintf(\"%s(%%d)\", uint64(v))", en.name)
+	e.p("}")
+	e.p("")
+}
+
+func emitStruct(e *emitter, s *structDef) {
+	e.p("// %s is the %s field structure of the protocol definition", s.name, s.name)
+	e.p("type %s struct {", s.name)
+	emitFieldDecls(e, s.fields)
+	e.p("}")
+	e.p("")
+
+	e.p("// encode writes the structure to the first %d bytes of b", s.size)
+	e.p("func (s *%s) encode(b []byte) {", s.name)
+	e.p("_ = b[%d]", s.size-1)
+	for _, f := range s.fields {
+		emitEncode(e, f, "s", "b")
+	}
+	e.p("}")
+	e.p("")
+
+	e.p("// decode reads the structure from the first %d bytes of b", s.size)
+	e.p("func (s *%s) decode(b []byte) {", s.name)
+	e.p("_ = b[%d]", s.size-1)
+	for _, f := range s.fields {
+		emitDecode(e, f, "s", "b")
+	}
+	e.p("}")
+	e.p("")
+}
+
+func emitMessage(e *emitter, pkt *packet) {
+	name := messageName(pkt)
+	named := hasNamedFields(pkt.fields)
+
+	e.p("// %s is the payload of a %s (%d) packet", name, pkt.constName, pkt.pktType)
+	if named {
+		e.p("type %s struct {", name)
+		emitFieldDecls(e, pkt.fields)
+		e.p("}")
+	} else {
+		e.p("type %s struct{}", name)
+	}
+	e.p("")
+
+	e.p("func (*%s) Type() PacketType { return %s }", name, pkt.constName)
+	e.p("")
+	e.p("func (*%s) Size() int { return %d }", name, pkt.size)
+	e.p("")
+
+	// Encoding
+	switch {
+	case named:
+		e.p("func (m *%s) AppendBinary(b []byte) ([]byte, error) {", name)
+		e.p("b, p := grow(b, %d)", pkt.size)
+		for _, f := range pkt.fields {
+			emitEncode(e, f, "m", "p")
+		}
+		e.p("return b, nil")
+	case pkt.size > 0:
+		e.p("func (*%s) AppendBinary(b []byte) ([]byte, error) {", name)
+		e.p("b, _ = grow(b, %d)", pkt.size)
+		e.p("return b, nil")
+	default:
+		e.p("func (*%s) AppendBinary(b []byte) ([]byte, error) {", name)
+		e.p("return b, nil")
+	}
+	e.p("}")
+	e.p("")
+
+	e.p("func (m *%s) MarshalBinary() ([]byte, error) {", name)
+	e.p("return m.AppendBinary(make([]byte, 0, %d))", pkt.size)
+	e.p("}")
+	e.p("")
+
+	// Decoding
+	receiver := "m"
+	if !named {
+		receiver = ""
+	}
+	if pkt.size == 0 {
+		e.p("func (*%s) UnmarshalBinary(data []byte) error {", name)
+		e.p("return nil")
+		e.p("}")
+		e.p("")
+		return
+	}
+	e.p("func (%s *%s) UnmarshalBinary(data []byte) error {", receiver, name)
+	e.p("if len(data) < %d {", pkt.size)
+	e.p("return errShortPayload(%s, %d, len(data))", pkt.constName, pkt.size)
+	e.p("}")
+	for _, f := range pkt.fields {
+		emitDecode(e, f, "m", "data")
+	}
+	e.p("return nil")
+	e.p("}")
+	e.p("")
+}
+
+func emitFieldDecls(e *emitter, fields []*field) {
+	for _, f := range fields {
+		if f.kind != kindReserved {
+			e.p("%s %s", f.name, f.goType)
+		}
+	}
+}
+
+// slice formats b[from:to] for a field at its offset
+func slice(b string, f *field) string {
+	return fmt.Sprintf("%s[%d:%d]", b, f.offset, f.offset+f.size)
+}
+
+// elemSlice formats the start of the i-th element of an array field
+func elemSlice(b string, f *field, elemSize int) string {
+	if f.offset == 0 {
+		return fmt.Sprintf("%s[i*%d:]", b, elemSize)
+	}
+	return fmt.Sprintf("%s[%d+i*%d:]", b, f.offset, elemSize)
+}
+
+func emitEncode(e *emitter, f *field, recv, b string) {
+	v := recv + "." + f.name
+
+	switch f.kind {
+	case kindReserved:
+		// Reserved bytes are left as zero
+	case kindUint8:
+		e.p("%s[%d] = %s", b, f.offset, v)
+	case kindBool:
+		e.p("%s[%d] = boolByte(%s)", b, f.offset, v)
+	case kindInt:
+		if f.signed {
+			e.p("binary.LittleEndian.PutUint%d(%s, uint%d(%s))", f.bits, slice(b, f), f.bits, v)
+		} else {
+			e.p("binary.LittleEndian.PutUint%d(%s, %s)", f.bits, slice(b, f), v)
+		}
+	case kindFloat32:
+		e.p("binary.LittleEndian.PutUint32(%s, math.Float32bits(%s))", slice(b, f), v)
+	case kindEnum:
+		if f.bits == 8 {
+			e.p("%s[%d] = uint8(%s)", b, f.offset, v)
+		} else {
+			e.p("binary.LittleEndian.PutUint%d(%s, uint%d(%s))", f.bits, slice(b, f), f.bits, v)
+		}
+	case kindBytes:
+		e.p("copy(%s, %s[:])", slice(b, f), v)
+	case kindColor:
+		e.p("putColor(%s, %s)", slice(b, f), v)
+	case kindStruct:
+		e.p("%s.encode(%s)", v, slice(b, f))
+	case kindColorArray:
+		e.p("for i := range %s {", v)
+		e.p("putColor(%s, %s[i])", elemSlice(b, f, 8), v)
+		e.p("}")
+	case kindStructArray:
+		e.p("for i := range %s {", v)
+		e.p("%s[i].encode(%s)", v, elemSlice(b, f, f.size/f.count))
+		e.p("}")
+	}
+}
+
+func emitDecode(e *emitter, f *field, recv, b string) {
+	v := recv + "." + f.name
+
+	switch f.kind {
+	case kindReserved:
+		// Reserved bytes are ignored
+	case kindUint8:
+		e.p("%s = %s[%d]", v, b, f.offset)
+	case kindBool:
+		e.p("%s = %s[%d] != 0", v, b, f.offset)
+	case kindInt:
+		if f.signed {
+			e.p("%s = int%d(binary.LittleEndian.Uint%d(%s))", v, f.bits, f.bits, slice(b, f))
+		} else {
+			e.p("%s = binary.LittleEndian.Uint%d(%s)", v, f.bits, slice(b, f))
+		}
+	case kindFloat32:
+		e.p("%s = math.Float32frombits(binary.LittleEndian.Uint32(%s))", v, slice(b, f))
+	case kindEnum:
+		if f.bits == 8 {
+			e.p("%s = %s(%s[%d])", v, f.goType, b, f.offset)
+		} else {
+			e.p("%s = %s(binary.LittleEndian.Uint%d(%s))", v, f.goType, f.bits, slice(b, f))
+		}
+	case kindBytes:
+		e.p("copy(%s[:], %s)", v, slice(b, f))
+	case kindColor:
+		e.p("%s = getColor(%s)", v, slice(b, f))
+	case kindStruct:
+		e.p("%s.decode(%s)", v, slice(b, f))
+	case kindColorArray:
+		e.p("for i := range %s {", v)
+		e.p("%s[i] = getColor(%s)", v, elemSlice(b, f, 8))
+		e.p("}")
+	case kindStructArray:
+		e.p("for i := range %s {", v)
+		e.p("%s[i].decode(%s)", v, elemSlice(b, f, f.size/f.count))
+		e.p("}")
+	}
+}
+
+// messageName returns the Go type name of a packet's message
+func messageName(pkt *packet) string {
+	return pkt.constName + "Message"
+}
+
+func hasNamedFields(fields []*field) bool {
+	for _, f := range fields {
+		if f.kind != kindReserved {
+			return true
+		}
+	}
+	return false
+}
+
+// namespaceTitle formats a packet namespace such as multi_zone as "Multi zone"
+func namespaceTitle(ns string) string {
+	words := strings.ReplaceAll(ns, "_", " ")
+	return strings.ToUpper(words[:1]) + words[1:]
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// protocol is the resolved protocol definition
+type protocol struct {
+	enums   []*enum
+	structs []*structDef
+	packets []*packet
+}
+
+type enum struct {
+	name   string // Go type name, as in the definition
+	base   string // Underlying unsigned integer type
+	values []enumValue
+}
+
+type enumValue struct {
+	goName    string // Go constant name, such as LightWaveformHalfSine
+	shortName string // Definition name without the enum prefix, such as HALF_SINE
+	value     uint64
+}
+
+type structDef struct {
+	name   string
+	size   int
+	fields []*field
+}
+
+type packet struct {
+	name      string // Definition name, such as LightSetWaveformOptional
+	namespace string
+	constName string // PacketType constant name, such as SetWaveformOptional
+	pktType   int
+	size      int
+	fields    []*field
+}
+
+// fieldKind describes how a field is encoded
+type fieldKind int
+
+const (
+	kindReserved fieldKind = iota
+	kindUint8
+	kindBool
+	kindInt         // Multi-byte integers
+	kindFloat32     // IEEE 754 single precision
+	kindBytes       // Fixed size byte array
+	kindEnum        // Named enum
+	kindColor       // HSBK color mapped to LIFXColor
+	kindStruct      // Named field structure
+	kindColorArray  // Fixed size array of colors
+	kindStructArray // Fixed size array of field structures
+)
+
+type field struct {
+	name   string // Go field name, empty for reserved bytes
+	kind   fieldKind
+	goType string // Go type of the field
+	bits   int    // Width of integer and enum fields
+	signed bool   // Integer fields only
+	enum   *enum
+	elem   string // Element type name of arrays, or struct name
+	count  int    // Element count of arrays
+	size   int
+	offset int
+}
+
+// colorStruct is the field structure mapped to LIFXColor
+const colorStruct = "Color"
+
+// constNames maps definition packet names to PacketType constant names where
+// removing the namespace prefix doesn't produce the name this package uses
+var constNames = map[string]string{
+	"LightGet":                        "GetColor",
+	"LightState":                      "LightState",
+	"LightGetPower":                   "GetLightPower",
+	"LightSetPower":                   "SetLightPower",
+	"LightStatePower":                 "StateLightPower",
+	"MultiZoneGetEffect":              "GetMultiZoneEffect",
+	"MultiZoneSetEffect":              "SetMultiZoneEffect",
+	"MultiZoneStateEffect":            "StateMultiZoneEffect",
+	"MultiZoneExtendedGetColorZones":  "GetExtendedColorZones",
+	"MultiZoneExtendedSetColorZones":  "SetExtendedColorZones",
+	"MultiZoneExtendedStateMultiZone": "StateExtendedColorZones",
+	"TileGetEffect":                   "GetTileEffect",
+	"TileSetEffect":                   "SetTileEffect",
+	"TileStateEffect":                 "StateTileEffect",
+	"SensorGetAmbientLight":           "SensorGetAmbientLight",
+	"SensorStateAmbientLight":         "StateAmbientLight",
+}
+
+// namespacePrefixes are removed from packet names to form constant names
+var namespacePrefixes = []string{"Device", "Light", "MultiZone", "Relay", "Sensor", "Tile"}
+
+// initialisms are written in upper case in Go identifiers
+var initialisms = map[string]string{"Udp": "UDP"}
+
+// buildProtocol resolves the parsed definition documents into a protocol
+func buildProtocol(docs []map[string]any) (*protocol, error) {
+	proto := &protocol{}
+
+	enums := map[string]*enum{}
+	rawStructs := map[string]map[string]any{}
+	for _, doc := range docs {
+		for name, raw := range asMap(doc["enums"]) {
+			if _, dup := enums[name]; dup {
+				return nil, fmt.Errorf("enum %s is defined twice", name)
+			}
+			e, err := buildEnum(name, asMap(raw))
+			if err != nil {
+				return nil, err
+			}
+			enums[name] = e
+			proto.enums = append(proto.enums, e)
+		}
+		for name, raw := range asMap(doc["fields"]) {
+			if _, dup := rawStructs[name]; dup {
+				return nil, fmt.Errorf("field structure %s is defined twice", name)
+			}
+			rawStructs[name] = asMap(raw)
+		}
+	}
+
+	for name, raw := range rawStructs {
+		if name == colorStruct {
+			continue // Encoded as LIFXColor
+		}
+		size, err := asInt(raw["size_bytes"])
+		if err != nil {
+			return nil, fmt.Errorf("field structure %s: %w", name, err)
+		}
+		fields, err := buildFields(asList(raw["fields"]), size, enums, rawStructs)
+		if err != nil {
+			return nil, fmt.Errorf("field structure %s: %w", name, err)
+		}
+		proto.structs = append(proto.structs, &structDef{name: name, size: size, fields: fields})
+	}
+
+	seenTypes := map[int]string{}
+	for _, doc := range docs {
+		for namespace, group := range asMap(doc["packets"]) {
+			for name, raw := range asMap(group) {
+				def := asMap(raw)
+				pktType, err := asInt(def["pkt_type"])
+				if err != nil {
+					return nil, fmt.Errorf("packet %s: %w", name, err)
+				}
+				if other, dup := seenTypes[pktType]; dup {
+					return nil, fmt.Errorf("packets %s and %s share type %d", other, name, pktType)
+				}
+				seenTypes[pktType] = name
+
+				size, err := asInt(def["size_bytes"])
+				if err != nil {
+					return nil, fmt.Errorf("packet %s: %w", name, err)
+				}
+				fields, err := buildFields(asList(def["fields"]), size, enums, rawStructs)
+				if err != nil {
+					return nil, fmt.Errorf("packet %s: %w", name, err)
+				}
+
+				proto.packets = append(proto.packets, &packet{
+					name:      name,
+					namespace: namespace,
+					constName: constName(name),
+					pktType:   pktType,
+					size:      size,
+					fields:    fields,
+				})
+			}
+		}
+	}
+
+	sort.Slice(proto.enums, func(i, j int) bool { return proto.enums[i].name < proto.enums[j].name })
+	sort.Slice(proto.structs, func(i, j int) bool { return proto.structs[i].name < proto.structs[j].name })
+	sort.Slice(proto.packets, func(i, j int) bool { return proto.packets[i].pktType < proto.packets[j].pktType })
+
+	return proto, nil
+}
+
+// buildEnum resolves an enum definition
+func buildEnum(name string, def map[string]any) (*enum, error) {
+	base, _ := def["type"].(string)
+	switch base {
+	case "uint8", "uint16", "uint32":
+	default:
+		return nil, fmt.Errorf("enum %s: unsupported type %q", name, base)
+	}
+
+	e := &enum{name: name, base: base}
+	prefix := screamingSnake(name) + "_"
+	for _, raw := range asList(def["values"]) {
+		v := asMap(raw)
+		protoName, _ := v["name"].(string)
+		value, err := asInt(v["value"])
+		if err != nil {
+			return nil, fmt.Errorf("enum %s value %s: %w", name, protoName, err)
+		}
+
+		short := strings.TrimPrefix(protoName, prefix)
+		e.values = append(e.values, enumValue{
+			goName:    name + camelFromSnake(short),
+			shortName: short,
+			value:     uint64(value),
+		})
+	}
+
+	return e, nil
+}
+
+// buildFields resolves a field list and checks it fills size bytes
+func buildFields(list []any, size int, enums map[string]*enum, structs map[string]map[string]any) ([]*field, error) {
+	var fields []*field
+	offset := 0
+
+	for _, raw := range list {
+		def := asMap(raw)
+		typ, _ := def["type"].(string)
+		name, _ := def["name"].(string)
+		fieldSize, err := asInt(def["size_bytes"])
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+
+		f, err := resolveField(typ, enums, structs)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		if f.kind != kindReserved {
+			if name == "" {
+				return nil, fmt.Errorf("field of type %s has no name", typ)
+			}
+			f.name = name
+		}
+		if f.size != 0 && f.size != fieldSize {
+			return nil, fmt.Errorf("field %s: type %s is %d bytes, definition says %d", name, typ, f.size, fieldSize)
+		}
+		f.size = fieldSize
+		f.offset = offset
+		offset += fieldSize
+
+		fields = append(fields, f)
+	}
+
+	if offset != size {
+		return nil, fmt.Errorf("fields total %d bytes, definition says %d", offset, size)
+	}
+
+	return fields, nil
+}
+
+// resolveField determines the encoding of a field type. The returned size is
+// zero when the type doesn't fix one.
+func resolveField(typ string, enums map[string]*enum, structs map[string]map[string]any) (*field, error) {
+	switch typ {
+	case "reserved":
+		return &field{kind: kindReserved}, nil
+	case "uint8":
+		return &field{kind: kindUint8, goType: "uint8", bits: 8, size: 1}, nil
+	case "bool":
+		return &field{kind: kindBool, goType: "bool", size: 1}, nil
+	case "uint16", "uint32", "uint64", "int16", "int32", "int64":
+		bits, _ := strconv.Atoi(strings.TrimLeft(typ, "uint"))
+		return &field{kind: kindInt, goType: typ, bits: bits, signed: typ[0] == 'i', size: bits / 8}, nil
+	case "float32":
+		return &field{kind: kindFloat32, goType: "float32", bits: 32, size: 4}, nil
+	}
+
+	// Fixed size arrays
+	if strings.HasPrefix(typ, "[") {
+		end := strings.Index(typ, "]")
+		if end < 0 {
+			return nil, fmt.Errorf("malformed type %q", typ)
+		}
+		count, err := strconv.Atoi(typ[1:end])
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("malformed array length in %q", typ)
+		}
+
+		elem := typ[end+1:]
+		switch {
+		case elem == "byte":
+			return &field{kind: kindBytes, goType: typ, count: count, size: count}, nil
+		case elem == "<"+colorStruct+">":
+			return &field{kind: kindColorArray, goType: fmt.Sprintf("[%d]LIFXColor", count), count: count, size: 8 * count}, nil
+		case strings.HasPrefix(elem, "<") && strings.HasSuffix(elem, ">"):
+			name := elem[1 : len(elem)-1]
+			def, ok := structs[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown field structure %s", name)
+			}
+			size, err := asInt(def["size_bytes"])
+			if err != nil {
+				return nil, err
+			}
+			return &field{kind: kindStructArray, goType: fmt.Sprintf("[%d]%s", count, name), elem: name, count: count, size: size * count}, nil
+		default:
+			return nil, fmt.Errorf("unsupported array element in %q", typ)
+		}
+	}
+
+	// References to enums and field structures
+	if strings.HasPrefix(typ, "<") && strings.HasSuffix(typ, ">") {
+		name := typ[1 : len(typ)-1]
+		if name == colorStruct {
+			return &field{kind: kindColor, goType: "LIFXColor", size: 8}, nil
+		}
+		if e, ok := enums[name]; ok {
+			bits, _ := strconv.Atoi(strings.TrimPrefix(e.base, "uint"))
+			return &field{kind: kindEnum, goType: name, enum: e, bits: bits, size: bits / 8}, nil
+		}
+		if def, ok := structs[name]; ok {
+			size, err := asInt(def["size_bytes"])
+			if err != nil {
+				return nil, err
+			}
+			return &field{kind: kindStruct, goType: name, elem: name, size: size}, nil
+		}
+		return nil, fmt.Errorf("unknown type %s", name)
+	}
+
+	return nil, fmt.Errorf("unsupported type %q", typ)
+}
+
+// constName returns the PacketType constant name for a definition packet name
+func constName(name string) string {
+	if c, ok := constNames[name]; ok {
+		return c
+	}
+	for _, prefix := range namespacePrefixes {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && rest != "" && unicode.IsUpper(rune(rest[0])) {
+			return rest
+		}
+	}
+	return name
+}
+
+// screamingSnake converts CamelCase to SCREAMING_SNAKE_CASE
+func screamingSnake(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// camelFromSnake converts SCREAMING_SNAKE_CASE to CamelCase
+func camelFromSnake(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		word := string(part[0]) + strings.ToLower(part[1:])
+		if upper, ok := initialisms[word]; ok {
+			word = upper
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asList(v any) []any {
+	l, _ := v.([]any)
+	return l
+}
+
+func asInt(v any) (int, error) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("expected a number, got %v", v)
+	}
+	return strconv.Atoi(s)
+}
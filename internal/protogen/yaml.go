@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The protocol definition only uses block mappings, block sequences of
+// mappings, scalars and the empty flow collections [] and {}. This reader
+// handles exactly that subset so the generator needs no dependencies.
+
+// line is a significant line of the document
+type line struct {
+	num    int
+	indent int
+	text   string
+}
+
+// yamlParser parses a document into map[string]any, []any and string values
+type yamlParser struct {
+	lines []line
+	pos   int
+}
+
+// parseYAML parses the protocol definition subset of YAML
+func parseYAML(data []byte) (map[string]any, error) {
+	p := &yamlParser{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for num := 1; scanner.Scan(); num++ {
+		raw := strings.TrimRight(scanner.Text(), " \t\r")
+		text := strings.TrimLeft(raw, " ")
+		if text == "" || strings.HasPrefix(text, "#") || text == "---" {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", num)
+		}
+		p.lines = append(p.lines, line{num: num, indent: len(raw) - len(text), text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(p.lines) == 0 {
+		return map[string]any{}, nil
+	}
+
+	root, err := p.parseMap(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+
+	return root, nil
+}
+
+// parseBlock parses the mapping or sequence starting at the current line
+func (p *yamlParser) parseBlock(indent int) (any, error) {
+	if strings.HasPrefix(p.lines[p.pos].text, "- ") || p.lines[p.pos].text == "-" {
+		return p.parseList(indent)
+	}
+
+	return p.parseMap(indent)
+}
+
+// parseMap parses consecutive "key: value" lines at the given indentation
+func (p *yamlParser) parseMap(indent int) (map[string]any, error) {
+	m := make(map[string]any)
+
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.num)
+		}
+		if strings.HasPrefix(l.text, "- ") {
+			break
+		}
+
+		key, value, ok := strings.Cut(l.text, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", l.num)
+		}
+		key = unquote(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", l.num, key)
+		}
+		p.pos++
+
+		if value != "" {
+			m[key] = scalar(value)
+			continue
+		}
+
+		// A key without a value introduces a nested block, which may be empty
+		if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+			child, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = child
+		} else if p.pos < len(p.lines) && p.lines[p.pos].indent == indent && strings.HasPrefix(p.lines[p.pos].text, "- ") {
+			// Sequences may be written at the same indentation as their key
+			child, err := p.parseList(indent)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = child
+		} else {
+			m[key] = nil
+		}
+	}
+
+	return m, nil
+}
+
+// parseList parses consecutive "- " items at the given indentation
+func (p *yamlParser) parseList(indent int) ([]any, error) {
+	var list []any
+
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent != indent || !strings.HasPrefix(l.text, "- ") {
+			if l.indent > indent {
+				return nil, fmt.Errorf("line %d: unexpected indentation", l.num)
+			}
+			break
+		}
+
+		item := strings.TrimSpace(strings.TrimPrefix(l.text, "- "))
+		if _, _, isMap := strings.Cut(item, ":"); !isMap || strings.HasPrefix(item, "\"") {
+			list = append(list, scalar(item))
+			p.pos++
+			continue
+		}
+
+		// Rewrite the item as a mapping line indented past the dash
+		itemIndent := indent + 2
+		p.lines[p.pos] = line{num: l.num, indent: itemIndent, text: item}
+		child, err := p.parseMap(itemIndent)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, child)
+	}
+
+	return list, nil
+}
+
+// scalar converts a scalar or empty flow collection to its value
+func scalar(s string) any {
+	switch s {
+	case "[]":
+		return []any{}
+	case "{}":
+		return map[string]any{}
+	}
+
+	return unquote(s)
+}
+
+// unquote removes surrounding quotes from a scalar
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"') {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+
+	return s
+}
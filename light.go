@@ -0,0 +1,31 @@
+package lifxlan
+
+// LightStateInfo is the state of a light reported in a LightState packet
+type LightStateInfo struct {
+	Color LIFXColor `json:"color"`
+	Power uint16    `json:"power"`
+	Label string    `json:"label"`
+}
+
+// GetState queries the color, power level and label of a light
+func (d *Device) GetState() (LightStateInfo, error) {
+	var state LightStateMessage
+	if err := d.request(&GetColorMessage{}, &state); err != nil {
+		return LightStateInfo{}, err
+	}
+
+	info := LightStateInfo{
+		Color: state.Color,
+		Power: state.Power,
+		Label: labelString(state.Label),
+	}
+
+	// Update the device's cached state
+	d.update(func(cached *DeviceInfo) {
+		cached.Color = info.Color
+		cached.Power = info.Power
+		cached.Label = info.Label
+	})
+
+	return info, nil
+}
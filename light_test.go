@@ -0,0 +1,66 @@
+package lifxlan
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestGetState(t *testing.T) {
+	color := NewColor(21845, 65535, 32768, 3500)
+
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetColor, &LightStateMessage{Color: color, Power: 65535, Label: labelBytes("Kitchen")})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	state, err := device.GetState()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := LightStateInfo{Color: color, Power: 65535, Label: "Kitchen"}
+	if state != want {
+		t.Errorf("got state %+v, want %+v", state, want)
+	}
+	if info := device.Info(); info.Color != color || info.Power != 65535 || info.Label != "Kitchen" {
+		t.Errorf("cached color %v, power %d and label %q, want the reported state", info.Color, info.Power, info.Label)
+	}
+
+	if n := len(sentPayloads(t, fake, device, GetColor)); n != 1 {
+		t.Errorf("sent %d GetColor packets, want 1", n)
+	}
+}
+
+func TestGetStateUnhandled(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetColor, &StateUnhandledMessage{UnhandledType: uint16(GetColor)})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	if _, err := device.GetState(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetState returned %v, want ErrUnsupported", err)
+	}
+}
+
+func TestColorJSON(t *testing.T) {
+	color := NewColor(1, 2, 3, 3500)
+
+	data, err := json.Marshal(color)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"hue":1,"saturation":2,"brightness":3,"kelvin":3500}`; string(data) != want {
+		t.Errorf("encoded %s, want %s", data, want)
+	}
+
+	var decoded LIFXColor
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != color {
+		t.Errorf("decoded %v, want %v", decoded, color)
+	}
+}
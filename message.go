@@ -0,0 +1,136 @@
+package lifxlan
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Message is the typed payload of a LIFX packet (https://lan.developer.lifx.com/docs/packet-contents#payload)
+type Message interface {
+	// Type returns the packet type that carries the message
+	Type() PacketType
+
+	// Size returns the encoded payload size in bytes
+	Size() int
+
+	// AppendBinary appends the encoded payload to b
+	AppendBinary(b []byte) ([]byte, error)
+
+	// MarshalBinary returns the encoded payload
+	MarshalBinary() ([]byte, error)
+
+	// UnmarshalBinary decodes a payload, ignoring any bytes past Size
+	UnmarshalBinary(data []byte) error
+}
+
+var (
+	// ErrShortPayload is returned when a payload is smaller than its message
+	ErrShortPayload = errors.New("payload too short")
+
+	// ErrUnknownPacketType is returned when no message is registered for a packet type
+	ErrUnknownPacketType = errors.New("unknown packet type")
+)
+
+// messageTypesMu guards messageTypes against concurrent registration
+var messageTypesMu sync.RWMutex
+
+// RegisterMessage registers the constructor used to decode a packet type,
+// replacing any existing one. It can be used to add vendor specific packets.
+func RegisterMessage(t PacketType, fn func() Message) {
+	messageTypesMu.Lock()
+	defer messageTypesMu.Unlock()
+
+	messageTypes[t] = fn
+}
+
+// NewMessage returns an empty message for the given packet type
+func NewMessage(t PacketType) (Message, error) {
+	messageTypesMu.RLock()
+	fn, ok := messageTypes[t]
+	messageTypesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownPacketType, uint16(t))
+	}
+
+	return fn(), nil
+}
+
+// DecodeMessage decodes a received frame into its header and typed message
+func DecodeMessage(frame []byte) (*Header, Message, error) {
+	h, err := ParseHeader(frame)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	msg, err := NewMessage(h.Type())
+	if err != nil {
+		return h, nil, err
+	}
+
+	if err := msg.UnmarshalBinary(frame[HeaderSize:]); err != nil {
+		return h, nil, err
+	}
+
+	return h, msg, nil
+}
+
+// BuildMessagePacket creates a packet carrying msg
+func BuildMessagePacket(source uint32, target []byte, msg Message) ([]byte, error) {
+	return AppendMessagePacket(make([]byte, 0, HeaderSize+msg.Size()), source, target, msg)
+}
+
+// AppendMessagePacket appends a packet carrying msg to dst
+func AppendMessagePacket(dst []byte, source uint32, target []byte, msg Message) ([]byte, error) {
+	dst = AppendHeader(dst, source, target, msg.Type(), uint16(msg.Size()))
+
+	return msg.AppendBinary(dst)
+}
+
+// errShortPayload reports a payload that is too small for its packet type
+func errShortPayload(t PacketType, want, got int) error {
+	return fmt.Errorf("%w: packet type %d needs %d bytes, got %d", ErrShortPayload, uint16(t), want, got)
+}
+
+// boolByte encodes a boolean field
+func boolByte(v bool) byte {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// putColor writes an HSBK color to the first 8 bytes of b
+func putColor(b []byte, c LIFXColor) {
+	_ = b[7]
+	binary.LittleEndian.PutUint16(b[0:2], c.hue)
+	binary.LittleEndian.PutUint16(b[2:4], c.saturation)
+	binary.LittleEndian.PutUint16(b[4:6], c.brightness)
+	binary.LittleEndian.PutUint16(b[6:8], c.kelvin)
+}
+
+// getColor reads an HSBK color from the first 8 bytes of b
+func getColor(b []byte) LIFXColor {
+	_ = b[7]
+	return NewColor(
+		binary.LittleEndian.Uint16(b[0:2]),
+		binary.LittleEndian.Uint16(b[2:4]),
+		binary.LittleEndian.Uint16(b[4:6]),
+		binary.LittleEndian.Uint16(b[6:8]),
+	)
+}
+
+// labelString converts a fixed size label field to a string
+func labelString(label [32]byte) string {
+	return string(bytes.TrimRight(label[:], "\x00"))
+}
+
+// labelBytes converts a string to a fixed size label field, truncating it to 32 bytes
+func labelBytes(label string) [32]byte {
+	var b [32]byte
+	copy(b[:], label)
+	return b
+}
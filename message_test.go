@@ -0,0 +1,144 @@
+package lifxlan
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func FuzzUnmarshalMessage(f *testing.F) {
+	// Seed every registered message with its zero value and a short payload
+	for pktType, newMessage := range messageTypes {
+		payload, err := newMessage().MarshalBinary()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(uint16(pktType), payload)
+		if len(payload) > 0 {
+			f.Add(uint16(pktType), payload[:len(payload)-1])
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, pktType uint16, payload []byte) {
+		msg, err := NewMessage(PacketType(pktType))
+		if err != nil {
+			return
+		}
+
+		if err := msg.UnmarshalBinary(payload); err != nil {
+			if len(payload) >= msg.Size() || !errors.Is(err, ErrShortPayload) {
+				t.Fatalf("%s: unexpected error for %d bytes: %v", msg.Type(), len(payload), err)
+			}
+			return
+		}
+
+		encoded, err := msg.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: %v", msg.Type(), err)
+		}
+		if len(encoded) != msg.Size() {
+			t.Fatalf("%s: encoded %d bytes, want %d", msg.Type(), len(encoded), msg.Size())
+		}
+
+		// Decoding the encoded message must reproduce it exactly
+		again, _ := NewMessage(msg.Type())
+		if err := again.UnmarshalBinary(encoded); err != nil {
+			t.Fatalf("%s: %v", msg.Type(), err)
+		}
+		reencoded, _ := again.MarshalBinary()
+		if !bytes.Equal(encoded, reencoded) {
+			t.Fatalf("%s: round trip changed\n% x\n% x", msg.Type(), encoded, reencoded)
+		}
+	})
+}
+
+func FuzzDecodeMessage(f *testing.F) {
+	f.Add(BuildDiscoveryPacket(1))
+	f.Add(BuildSetLabelPacket(1, benchTarget, "Kitchen"))
+	f.Add(BuildEchoRequestPacket(1, benchTarget, make([]byte, 64)))
+
+	f.Fuzz(func(t *testing.T, frame []byte) {
+		h, msg, err := DecodeMessage(frame)
+		if err != nil {
+			return
+		}
+
+		if msg.Type() != h.Type() {
+			t.Fatalf("decoded %s from a %s frame", msg.Type(), h.Type())
+		}
+	})
+}
+
+// TestMessagesGolden checks messages with reserved fields against layouts
+// worked out by hand from the protocol documentation
+func TestMessagesGolden(t *testing.T) {
+	chain := &StateDeviceChainMessage{TileDevicesCount: 1}
+	chain.TileDevices[0] = TileStateDevice{
+		AccelMeasX:           -1,
+		AccelMeasY:           1000,
+		UserX:                1.5,
+		UserY:                -0.5,
+		Width:                8,
+		Height:               8,
+		DeviceVersionVendor:  1,
+		DeviceVersionProduct: 55,
+		FirmwareBuild:        0x0123456789abcdef,
+		FirmwareVersionMinor: 50,
+		FirmwareVersionMajor: 3,
+	}
+
+	// The remaining 15 tiles are zero, followed by the tile count
+	chainWant := unhex(t, `00
+		ff ff e8 03 00 00 00 00  00 00 c0 3f 00 00 00 bf
+		08 08 00 01 00 00 00 37  00 00 00 00 00 00 00 ef
+		cd ab 89 67 45 23 01 00  00 00 00 00 00 00 00 32
+		00 03 00 00 00 00 00`)
+	chainWant = append(chainWant, make([]byte, 15*55)...)
+	chainWant = append(chainWant, 0x01)
+
+	tests := []struct {
+		name string
+		msg  Message
+		want []byte
+	}{
+		{
+			name: "SetWaveformOptional",
+			msg: &SetWaveformOptionalMessage{
+				Transient:     true,
+				Color:         NewColor(0x5555, 0xffff, 0x8000, 3500),
+				Period:        1000,
+				Cycles:        2.5,
+				SkewRatio:     -16384,
+				Waveform:      LightWaveformPulse,
+				SetBrightness: true,
+			},
+			want: unhex(t, `00 01 55 55 ff ff 00 80  ac 0d e8 03 00 00 00 00
+			                20 40 00 c0 04 00 00 01  00`),
+		},
+		{
+			name: "StateDeviceChain",
+			msg:  chain,
+			want: chainWant,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.msg.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("%s encoded\n% x\nwant\n% x", tt.name, got, tt.want)
+		}
+
+		// Decoding the expected bytes reproduces the message
+		decoded, _ := NewMessage(tt.msg.Type())
+		if err := decoded.UnmarshalBinary(tt.want); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(decoded, tt.msg) {
+			t.Errorf("%s decoded to %+v, want %+v", tt.name, decoded, tt.msg)
+		}
+	}
+}
@@ -0,0 +1,2495 @@
+// Code generated by protogen from protocol.yml, protocol_extra.yml. DO NOT EDIT.
+
+package lifxlan
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// DeviceService is the DeviceService enum of the protocol definition
+type DeviceService uint8
+
+const (
+	DeviceServiceUDP       DeviceService = 1
+	DeviceServiceReserved1 DeviceService = 2
+	DeviceServiceReserved2 DeviceService = 3
+	DeviceServiceReserved3 DeviceService = 4
+	DeviceServiceReserved4 DeviceService = 5
+)
+
+// String returns the definition name of the value
+func (v DeviceService) String() string {
+	switch v {
+	case DeviceServiceUDP:
+		return "UDP"
+	case DeviceServiceReserved1:
+		return "RESERVED1"
+	case DeviceServiceReserved2:
+		return "RESERVED2"
+	case DeviceServiceReserved3:
+		return "RESERVED3"
+	case DeviceServiceReserved4:
+		return "RESERVED4"
+	}
+	return fmt.Sprintf("DeviceService(%d)", uint64(v))
+}
+
+// LightLastHevCycleResult is the LightLastHevCycleResult enum of the protocol definition
+type LightLastHevCycleResult uint8
+
+const (
+	LightLastHevCycleResultSuccess              LightLastHevCycleResult = 0
+	LightLastHevCycleResultBusy                 LightLastHevCycleResult = 1
+	LightLastHevCycleResultInterruptedByReset   LightLastHevCycleResult = 2
+	LightLastHevCycleResultInterruptedByHomekit LightLastHevCycleResult = 3
+	LightLastHevCycleResultInterruptedByLan     LightLastHevCycleResult = 4
+	LightLastHevCycleResultInterruptedByCloud   LightLastHevCycleResult = 5
+	LightLastHevCycleResultNone                 LightLastHevCycleResult = 255
+)
+
+// String returns the definition name of the value
+func (v LightLastHevCycleResult) String() string {
+	switch v {
+	case LightLastHevCycleResultSuccess:
+		return "SUCCESS"
+	case LightLastHevCycleResultBusy:
+		return "BUSY"
+	case LightLastHevCycleResultInterruptedByReset:
+		return "INTERRUPTED_BY_RESET"
+	case LightLastHevCycleResultInterruptedByHomekit:
+		return "INTERRUPTED_BY_HOMEKIT"
+	case LightLastHevCycleResultInterruptedByLan:
+		return "INTERRUPTED_BY_LAN"
+	case LightLastHevCycleResultInterruptedByCloud:
+		return "INTERRUPTED_BY_CLOUD"
+	case LightLastHevCycleResultNone:
+		return "NONE"
+	}
+	return fmt.Sprintf("LightLastHevCycleResult(%d)", uint64(v))
+}
+
+// LightWaveform is the LightWaveform enum of the protocol definition
+type LightWaveform uint8
+
+const (
+	LightWaveformSaw      LightWaveform = 0
+	LightWaveformSine     LightWaveform = 1
+	LightWaveformHalfSine LightWaveform = 2
+	LightWaveformTriangle LightWaveform = 3
+	LightWaveformPulse    LightWaveform = 4
+)
+
+// String returns the definition name of the value
+func (v LightWaveform) String() string {
+	switch v {
+	case LightWaveformSaw:
+		return "SAW"
+	case LightWaveformSine:
+		return "SINE"
+	case LightWaveformHalfSine:
+		return "HALF_SINE"
+	case LightWaveformTriangle:
+		return "TRIANGLE"
+	case LightWaveformPulse:
+		return "PULSE"
+	}
+	return fmt.Sprintf("LightWaveform(%d)", uint64(v))
+}
+
+// MultiZoneApplicationRequest is the MultiZoneApplicationRequest enum of the protocol definition
+type MultiZoneApplicationRequest uint8
+
+const (
+	MultiZoneApplicationRequestNoApply   MultiZoneApplicationRequest = 0
+	MultiZoneApplicationRequestApply     MultiZoneApplicationRequest = 1
+	MultiZoneApplicationRequestApplyOnly MultiZoneApplicationRequest = 2
+)
+
+// String returns the definition name of the value
+func (v MultiZoneApplicationRequest) String() string {
+	switch v {
+	case MultiZoneApplicationRequestNoApply:
+		return "NO_APPLY"
+	case MultiZoneApplicationRequestApply:
+		return "APPLY"
+	case MultiZoneApplicationRequestApplyOnly:
+		return "APPLY_ONLY"
+	}
+	return fmt.Sprintf("MultiZoneApplicationRequest(%d)", uint64(v))
+}
+
+// MultiZoneEffectMoveDirection is the MultiZoneEffectMoveDirection enum of the protocol definition
+type MultiZoneEffectMoveDirection uint32
+
+const (
+	MultiZoneEffectMoveDirectionTowards MultiZoneEffectMoveDirection = 0
+	MultiZoneEffectMoveDirectionAway    MultiZoneEffectMoveDirection = 1
+)
+
+// String returns the definition name of the value
+func (v MultiZoneEffectMoveDirection) String() string {
+	switch v {
+	case MultiZoneEffectMoveDirectionTowards:
+		return "TOWARDS"
+	case MultiZoneEffectMoveDirectionAway:
+		return "AWAY"
+	}
+	return fmt.Sprintf("MultiZoneEffectMoveDirection(%d)", uint64(v))
+}
+
+// MultiZoneEffectType is the MultiZoneEffectType enum of the protocol definition
+type MultiZoneEffectType uint8
+
+const (
+	MultiZoneEffectTypeOff       MultiZoneEffectType = 0
+	MultiZoneEffectTypeMove      MultiZoneEffectType = 1
+	MultiZoneEffectTypeReserved1 MultiZoneEffectType = 2
+	MultiZoneEffectTypeReserved2 MultiZoneEffectType = 3
+)
+
+// String returns the definition name of the value
+func (v MultiZoneEffectType) String() string {
+	switch v {
+	case MultiZoneEffectTypeOff:
+		return "OFF"
+	case MultiZoneEffectTypeMove:
+		return "MOVE"
+	case MultiZoneEffectTypeReserved1:
+		return "RESERVED1"
+	case MultiZoneEffectTypeReserved2:
+		return "RESERVED2"
+	}
+	return fmt.Sprintf("MultiZoneEffectType(%d)", uint64(v))
+}
+
+// MultiZoneExtendedApplicationRequest is the MultiZoneExtendedApplicationRequest enum of the protocol definition
+type MultiZoneExtendedApplicationRequest uint8
+
+const (
+	MultiZoneExtendedApplicationRequestNoApply   MultiZoneExtendedApplicationRequest = 0
+	MultiZoneExtendedApplicationRequestApply     MultiZoneExtendedApplicationRequest = 1
+	MultiZoneExtendedApplicationRequestApplyOnly MultiZoneExtendedApplicationRequest = 2
+)
+
+// String returns the definition name of the value
+func (v MultiZoneExtendedApplicationRequest) String() string {
+	switch v {
+	case MultiZoneExtendedApplicationRequestNoApply:
+		return "NO_APPLY"
+	case MultiZoneExtendedApplicationRequestApply:
+		return "APPLY"
+	case MultiZoneExtendedApplicationRequestApplyOnly:
+		return "APPLY_ONLY"
+	}
+	return fmt.Sprintf("MultiZoneExtendedApplicationRequest(%d)", uint64(v))
+}
+
+// TileEffectSkyType is the TileEffectSkyType enum of the protocol definition
+type TileEffectSkyType uint8
+
+const (
+	TileEffectSkyTypeSunrise TileEffectSkyType = 0
+	TileEffectSkyTypeSunset  TileEffectSkyType = 1
+	TileEffectSkyTypeClouds  TileEffectSkyType = 2
+)
+
+// String returns the definition name of the value
+func (v TileEffectSkyType) String() string {
+	switch v {
+	case TileEffectSkyTypeSunrise:
+		return "SUNRISE"
+	case TileEffectSkyTypeSunset:
+		return "SUNSET"
+	case TileEffectSkyTypeClouds:
+		return "CLOUDS"
+	}
+	return fmt.Sprintf("TileEffectSkyType(%d)", uint64(v))
+}
+
+// TileEffectType is the TileEffectType enum of the protocol definition
+type TileEffectType uint8
+
+const (
+	TileEffectTypeOff       TileEffectType = 0
+	TileEffectTypeReserved1 TileEffectType = 1
+	TileEffectTypeMorph     TileEffectType = 2
+	TileEffectTypeFlame     TileEffectType = 3
+	TileEffectTypeReserved2 TileEffectType = 4
+	TileEffectTypeSky       TileEffectType = 5
+)
+
+// String returns the definition name of the value
+func (v TileEffectType) String() string {
+	switch v {
+	case TileEffectTypeOff:
+		return "OFF"
+	case TileEffectTypeReserved1:
+		return "RESERVED1"
+	case TileEffectTypeMorph:
+		return "MORPH"
+	case TileEffectTypeFlame:
+		return "FLAME"
+	case TileEffectTypeReserved2:
+		return "RESERVED2"
+	case TileEffectTypeSky:
+		return "SKY"
+	}
+	return fmt.Sprintf("TileEffectType(%d)", uint64(v))
+}
+
+// MultiZoneEffectParameter is the MultiZoneEffectParameter field structure of the protocol definition
+type MultiZoneEffectParameter struct {
+	Direction MultiZoneEffectMoveDirection
+}
+
+// encode writes the structure to the first 32 bytes of b
+func (s *MultiZoneEffectParameter) encode(b []byte) {
+	_ = b[31]
+	binary.LittleEndian.PutUint32(b[4:8], uint32(s.Direction))
+}
+
+// decode reads the structure from the first 32 bytes of b
+func (s *MultiZoneEffectParameter) decode(b []byte) {
+	_ = b[31]
+	s.Direction = MultiZoneEffectMoveDirection(binary.LittleEndian.Uint32(b[4:8]))
+}
+
+// MultiZoneEffectSettings is the MultiZoneEffectSettings field structure of the protocol definition
+type MultiZoneEffectSettings struct {
+	Instanceid uint32
+	Type       MultiZoneEffectType
+	Speed      uint32
+	Duration   uint64
+	Parameters MultiZoneEffectParameter
+}
+
+// encode writes the structure to the first 59 bytes of b
+func (s *MultiZoneEffectSettings) encode(b []byte) {
+	_ = b[58]
+	binary.LittleEndian.PutUint32(b[0:4], s.Instanceid)
+	b[4] = uint8(s.Type)
+	binary.LittleEndian.PutUint32(b[7:11], s.Speed)
+	binary.LittleEndian.PutUint64(b[11:19], s.Duration)
+	s.Parameters.encode(b[27:59])
+}
+
+// decode reads the structure from the first 59 bytes of b
+func (s *MultiZoneEffectSettings) decode(b []byte) {
+	_ = b[58]
+	s.Instanceid = binary.LittleEndian.Uint32(b[0:4])
+	s.Type = MultiZoneEffectType(b[4])
+	s.Speed = binary.LittleEndian.Uint32(b[7:11])
+	s.Duration = binary.LittleEndian.Uint64(b[11:19])
+	s.Parameters.decode(b[27:59])
+}
+
+// TileBufferRect is the TileBufferRect field structure of the protocol definition
+type TileBufferRect struct {
+	FbIndex uint8
+	X       uint8
+	Y       uint8
+	Width   uint8
+}
+
+// encode writes the structure to the first 4 bytes of b
+func (s *TileBufferRect) encode(b []byte) {
+	_ = b[3]
+	b[0] = s.FbIndex
+	b[1] = s.X
+	b[2] = s.Y
+	b[3] = s.Width
+}
+
+// decode reads the structure from the first 4 bytes of b
+func (s *TileBufferRect) decode(b []byte) {
+	_ = b[3]
+	s.FbIndex = b[0]
+	s.X = b[1]
+	s.Y = b[2]
+	s.Width = b[3]
+}
+
+// TileEffectParameter is the TileEffectParameter field structure of the protocol definition
+type TileEffectParameter struct {
+	SkyType            TileEffectSkyType
+	CloudSaturationMin uint8
+	CloudSaturationMax uint8
+}
+
+// encode writes the structure to the first 32 bytes of b
+func (s *TileEffectParameter) encode(b []byte) {
+	_ = b[31]
+	b[0] = uint8(s.SkyType)
+	b[4] = s.CloudSaturationMin
+	b[8] = s.CloudSaturationMax
+}
+
+// decode reads the structure from the first 32 bytes of b
+func (s *TileEffectParameter) decode(b []byte) {
+	_ = b[31]
+	s.SkyType = TileEffectSkyType(b[0])
+	s.CloudSaturationMin = b[4]
+	s.CloudSaturationMax = b[8]
+}
+
+// TileEffectSettings is the TileEffectSettings field structure of the protocol definition
+type TileEffectSettings struct {
+	Instanceid   uint32
+	Type         TileEffectType
+	Speed        uint32
+	Duration     uint64
+	Parameters   TileEffectParameter
+	PaletteCount uint8
+	Palette      [16]LIFXColor
+}
+
+// encode writes the structure to the first 188 bytes of b
+func (s *TileEffectSettings) encode(b []byte) {
+	_ = b[187]
+	binary.LittleEndian.PutUint32(b[0:4], s.Instanceid)
+	b[4] = uint8(s.Type)
+	binary.LittleEndian.PutUint32(b[7:11], s.Speed)
+	binary.LittleEndian.PutUint64(b[11:19], s.Duration)
+	s.Parameters.encode(b[27:59])
+	b[59] = s.PaletteCount
+	for i := range s.Palette {
+		putColor(b[60+i*8:], s.Palette[i])
+	}
+}
+
+// decode reads the structure from the first 188 bytes of b
+func (s *TileEffectSettings) decode(b []byte) {
+	_ = b[187]
+	s.Instanceid = binary.LittleEndian.Uint32(b[0:4])
+	s.Type = TileEffectType(b[4])
+	s.Speed = binary.LittleEndian.Uint32(b[7:11])
+	s.Duration = binary.LittleEndian.Uint64(b[11:19])
+	s.Parameters.decode(b[27:59])
+	s.PaletteCount = b[59]
+	for i := range s.Palette {
+		s.Palette[i] = getColor(b[60+i*8:])
+	}
+}
+
+// TileStateDevice is the TileStateDevice field structure of the protocol definition
+type TileStateDevice struct {
+	AccelMeasX           int16
+	AccelMeasY           int16
+	AccelMeasZ           int16
+	UserX                float32
+	UserY                float32
+	Width                uint8
+	Height               uint8
+	DeviceVersionVendor  uint32
+	DeviceVersionProduct uint32
+	FirmwareBuild        uint64
+	FirmwareVersionMinor uint16
+	FirmwareVersionMajor uint16
+}
+
+// encode writes the structure to the first 55 bytes of b
+func (s *TileStateDevice) encode(b []byte) {
+	_ = b[54]
+	binary.LittleEndian.PutUint16(b[0:2], uint16(s.AccelMeasX))
+	binary.LittleEndian.PutUint16(b[2:4], uint16(s.AccelMeasY))
+	binary.LittleEndian.PutUint16(b[4:6], uint16(s.AccelMeasZ))
+	binary.LittleEndian.PutUint32(b[8:12], math.Float32bits(s.UserX))
+	binary.LittleEndian.PutUint32(b[12:16], math.Float32bits(s.UserY))
+	b[16] = s.Width
+	b[17] = s.Height
+	binary.LittleEndian.PutUint32(b[19:23], s.DeviceVersionVendor)
+	binary.LittleEndian.PutUint32(b[23:27], s.DeviceVersionProduct)
+	binary.LittleEndian.PutUint64(b[31:39], s.FirmwareBuild)
+	binary.LittleEndian.PutUint16(b[47:49], s.FirmwareVersionMinor)
+	binary.LittleEndian.PutUint16(b[49:51], s.FirmwareVersionMajor)
+}
+
+// decode reads the structure from the first 55 bytes of b
+func (s *TileStateDevice) decode(b []byte) {
+	_ = b[54]
+	s.AccelMeasX = int16(binary.LittleEndian.Uint16(b[0:2]))
+	s.AccelMeasY = int16(binary.LittleEndian.Uint16(b[2:4]))
+	s.AccelMeasZ = int16(binary.LittleEndian.Uint16(b[4:6]))
+	s.UserX = math.Float32frombits(binary.LittleEndian.Uint32(b[8:12]))
+	s.UserY = math.Float32frombits(binary.LittleEndian.Uint32(b[12:16]))
+	s.Width = b[16]
+	s.Height = b[17]
+	s.DeviceVersionVendor = binary.LittleEndian.Uint32(b[19:23])
+	s.DeviceVersionProduct = binary.LittleEndian.Uint32(b[23:27])
+	s.FirmwareBuild = binary.LittleEndian.Uint64(b[31:39])
+	s.FirmwareVersionMinor = binary.LittleEndian.Uint16(b[47:49])
+	s.FirmwareVersionMajor = binary.LittleEndian.Uint16(b[49:51])
+}
+
+// GetServiceMessage is the payload of a GetService (2) packet
+type GetServiceMessage struct{}
+
+func (*GetServiceMessage) Type() PacketType { return GetService }
+
+func (*GetServiceMessage) Size() int { return 0 }
+
+func (*GetServiceMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetServiceMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetServiceMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// StateServiceMessage is the payload of a StateService (3) packet
+type StateServiceMessage struct {
+	Service DeviceService
+	Port    uint32
+}
+
+func (*StateServiceMessage) Type() PacketType { return StateService }
+
+func (*StateServiceMessage) Size() int { return 5 }
+
+func (m *StateServiceMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 5)
+	p[0] = uint8(m.Service)
+	binary.LittleEndian.PutUint32(p[1:5], m.Port)
+	return b, nil
+}
+
+func (m *StateServiceMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 5))
+}
+
+func (m *StateServiceMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 5 {
+		return errShortPayload(StateService, 5, len(data))
+	}
+	m.Service = DeviceService(data[0])
+	m.Port = binary.LittleEndian.Uint32(data[1:5])
+	return nil
+}
+
+// GetHostFirmwareMessage is the payload of a GetHostFirmware (14) packet
+type GetHostFirmwareMessage struct{}
+
+func (*GetHostFirmwareMessage) Type() PacketType { return GetHostFirmware }
+
+func (*GetHostFirmwareMessage) Size() int { return 0 }
+
+func (*GetHostFirmwareMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetHostFirmwareMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetHostFirmwareMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// StateHostFirmwareMessage is the payload of a StateHostFirmware (15) packet
+type StateHostFirmwareMessage struct {
+	Build        uint64
+	VersionMinor uint16
+	VersionMajor uint16
+}
+
+func (*StateHostFirmwareMessage) Type() PacketType { return StateHostFirmware }
+
+func (*StateHostFirmwareMessage) Size() int { return 20 }
+
+func (m *StateHostFirmwareMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 20)
+	binary.LittleEndian.PutUint64(p[0:8], m.Build)
+	binary.LittleEndian.PutUint16(p[16:18], m.VersionMinor)
+	binary.LittleEndian.PutUint16(p[18:20], m.VersionMajor)
+	return b, nil
+}
+
+func (m *StateHostFirmwareMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 20))
+}
+
+func (m *StateHostFirmwareMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 20 {
+		return errShortPayload(StateHostFirmware, 20, len(data))
+	}
+	m.Build = binary.LittleEndian.Uint64(data[0:8])
+	m.VersionMinor = binary.LittleEndian.Uint16(data[16:18])
+	m.VersionMajor = binary.LittleEndian.Uint16(data[18:20])
+	return nil
+}
+
+// GetWifiInfoMessage is the payload of a GetWifiInfo (16) packet
+type GetWifiInfoMessage struct{}
+
+func (*GetWifiInfoMessage) Type() PacketType { return GetWifiInfo }
+
+func (*GetWifiInfoMessage) Size() int { return 0 }
+
+func (*GetWifiInfoMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetWifiInfoMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetWifiInfoMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// StateWifiInfoMessage is the payload of a StateWifiInfo (17) packet
+type StateWifiInfoMessage struct {
+	Signal float32
+}
+
+func (*StateWifiInfoMessage) Type() PacketType { return StateWifiInfo }
+
+func (*StateWifiInfoMessage) Size() int { return 14 }
+
+func (m *StateWifiInfoMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 14)
+	binary.LittleEndian.PutUint32(p[0:4], math.Float32bits(m.Signal))
+	return b, nil
+}
+
+func (m *StateWifiInfoMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 14))
+}
+
+func (m *StateWifiInfoMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 14 {
+		return errShortPayload(StateWifiInfo, 14, len(data))
+	}
+	m.Signal = math.Float32frombits(binary.LittleEndian.Uint32(data[0:4]))
+	return nil
+}
+
+// GetWifiFirmwareMessage is the payload of a GetWifiFirmware (18) packet
+type GetWifiFirmwareMessage struct{}
+
+func (*GetWifiFirmwareMessage) Type() PacketType { return GetWifiFirmware }
+
+func (*GetWifiFirmwareMessage) Size() int { return 0 }
+
+func (*GetWifiFirmwareMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetWifiFirmwareMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetWifiFirmwareMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// StateWifiFirmwareMessage is the payload of a StateWifiFirmware (19) packet
+type StateWifiFirmwareMessage struct {
+	Build        uint64
+	VersionMinor uint16
+	VersionMajor uint16
+}
+
+func (*StateWifiFirmwareMessage) Type() PacketType { return StateWifiFirmware }
+
+func (*StateWifiFirmwareMessage) Size() int { return 20 }
+
+func (m *StateWifiFirmwareMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 20)
+	binary.LittleEndian.PutUint64(p[0:8], m.Build)
+	binary.LittleEndian.PutUint16(p[16:18], m.VersionMinor)
+	binary.LittleEndian.PutUint16(p[18:20], m.VersionMajor)
+	return b, nil
+}
+
+func (m *StateWifiFirmwareMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 20))
+}
+
+func (m *StateWifiFirmwareMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 20 {
+		return errShortPayload(StateWifiFirmware, 20, len(data))
+	}
+	m.Build = binary.LittleEndian.Uint64(data[0:8])
+	m.VersionMinor = binary.LittleEndian.Uint16(data[16:18])
+	m.VersionMajor = binary.LittleEndian.Uint16(data[18:20])
+	return nil
+}
+
+// GetPowerMessage is the payload of a GetPower (20) packet
+type GetPowerMessage struct{}
+
+func (*GetPowerMessage) Type() PacketType { return GetPower }
+
+func (*GetPowerMessage) Size() int { return 0 }
+
+func (*GetPowerMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetPowerMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetPowerMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// SetPowerMessage is the payload of a SetPower (21) packet
+type SetPowerMessage struct {
+	Level uint16
+}
+
+func (*SetPowerMessage) Type() PacketType { return SetPower }
+
+func (*SetPowerMessage) Size() int { return 2 }
+
+func (m *SetPowerMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 2)
+	binary.LittleEndian.PutUint16(p[0:2], m.Level)
+	return b, nil
+}
+
+func (m *SetPowerMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 2))
+}
+
+func (m *SetPowerMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errShortPayload(SetPower, 2, len(data))
+	}
+	m.Level = binary.LittleEndian.Uint16(data[0:2])
+	return nil
+}
+
+// StatePowerMessage is the payload of a StatePower (22) packet
+type StatePowerMessage struct {
+	Level uint16
+}
+
+func (*StatePowerMessage) Type() PacketType { return StatePower }
+
+func (*StatePowerMessage) Size() int { return 2 }
+
+func (m *StatePowerMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 2)
+	binary.LittleEndian.PutUint16(p[0:2], m.Level)
+	return b, nil
+}
+
+func (m *StatePowerMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 2))
+}
+
+func (m *StatePowerMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errShortPayload(StatePower, 2, len(data))
+	}
+	m.Level = binary.LittleEndian.Uint16(data[0:2])
+	return nil
+}
+
+// GetLabelMessage is the payload of a GetLabel (23) packet
+type GetLabelMessage struct{}
+
+func (*GetLabelMessage) Type() PacketType { return GetLabel }
+
+func (*GetLabelMessage) Size() int { return 0 }
+
+func (*GetLabelMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetLabelMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetLabelMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// SetLabelMessage is the payload of a SetLabel (24) packet
+type SetLabelMessage struct {
+	Label [32]byte
+}
+
+func (*SetLabelMessage) Type() PacketType { return SetLabel }
+
+func (*SetLabelMessage) Size() int { return 32 }
+
+func (m *SetLabelMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 32)
+	copy(p[0:32], m.Label[:])
+	return b, nil
+}
+
+func (m *SetLabelMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 32))
+}
+
+func (m *SetLabelMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 32 {
+		return errShortPayload(SetLabel, 32, len(data))
+	}
+	copy(m.Label[:], data[0:32])
+	return nil
+}
+
+// StateLabelMessage is the payload of a StateLabel (25) packet
+type StateLabelMessage struct {
+	Label [32]byte
+}
+
+func (*StateLabelMessage) Type() PacketType { return StateLabel }
+
+func (*StateLabelMessage) Size() int { return 32 }
+
+func (m *StateLabelMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 32)
+	copy(p[0:32], m.Label[:])
+	return b, nil
+}
+
+func (m *StateLabelMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 32))
+}
+
+func (m *StateLabelMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 32 {
+		return errShortPayload(StateLabel, 32, len(data))
+	}
+	copy(m.Label[:], data[0:32])
+	return nil
+}
+
+// GetVersionMessage is the payload of a GetVersion (32) packet
+type GetVersionMessage struct{}
+
+func (*GetVersionMessage) Type() PacketType { return GetVersion }
+
+func (*GetVersionMessage) Size() int { return 0 }
+
+func (*GetVersionMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetVersionMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetVersionMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// StateVersionMessage is the payload of a StateVersion (33) packet
+type StateVersionMessage struct {
+	Vendor  uint32
+	Product uint32
+}
+
+func (*StateVersionMessage) Type() PacketType { return StateVersion }
+
+func (*StateVersionMessage) Size() int { return 12 }
+
+func (m *StateVersionMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 12)
+	binary.LittleEndian.PutUint32(p[0:4], m.Vendor)
+	binary.LittleEndian.PutUint32(p[4:8], m.Product)
+	return b, nil
+}
+
+func (m *StateVersionMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 12))
+}
+
+func (m *StateVersionMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 12 {
+		return errShortPayload(StateVersion, 12, len(data))
+	}
+	m.Vendor = binary.LittleEndian.Uint32(data[0:4])
+	m.Product = binary.LittleEndian.Uint32(data[4:8])
+	return nil
+}
+
+// GetInfoMessage is the payload of a GetInfo (34) packet
+type GetInfoMessage struct{}
+
+func (*GetInfoMessage) Type() PacketType { return GetInfo }
+
+func (*GetInfoMessage) Size() int { return 0 }
+
+func (*GetInfoMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetInfoMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetInfoMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// StateInfoMessage is the payload of a StateInfo (35) packet
+type StateInfoMessage struct {
+	Time     uint64
+	Uptime   uint64
+	Downtime uint64
+}
+
+func (*StateInfoMessage) Type() PacketType { return StateInfo }
+
+func (*StateInfoMessage) Size() int { return 24 }
+
+func (m *StateInfoMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 24)
+	binary.LittleEndian.PutUint64(p[0:8], m.Time)
+	binary.LittleEndian.PutUint64(p[8:16], m.Uptime)
+	binary.LittleEndian.PutUint64(p[16:24], m.Downtime)
+	return b, nil
+}
+
+func (m *StateInfoMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 24))
+}
+
+func (m *StateInfoMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 24 {
+		return errShortPayload(StateInfo, 24, len(data))
+	}
+	m.Time = binary.LittleEndian.Uint64(data[0:8])
+	m.Uptime = binary.LittleEndian.Uint64(data[8:16])
+	m.Downtime = binary.LittleEndian.Uint64(data[16:24])
+	return nil
+}
+
+// SetRebootMessage is the payload of a SetReboot (38) packet
+type SetRebootMessage struct{}
+
+func (*SetRebootMessage) Type() PacketType { return SetReboot }
+
+func (*SetRebootMessage) Size() int { return 0 }
+
+func (*SetRebootMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *SetRebootMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*SetRebootMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// AcknowledgementMessage is the payload of a Acknowledgement (45) packet
+type AcknowledgementMessage struct{}
+
+func (*AcknowledgementMessage) Type() PacketType { return Acknowledgement }
+
+func (*AcknowledgementMessage) Size() int { return 0 }
+
+func (*AcknowledgementMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *AcknowledgementMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*AcknowledgementMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// GetLocationMessage is the payload of a GetLocation (48) packet
+type GetLocationMessage struct{}
+
+func (*GetLocationMessage) Type() PacketType { return GetLocation }
+
+func (*GetLocationMessage) Size() int { return 0 }
+
+func (*GetLocationMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetLocationMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetLocationMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// SetLocationMessage is the payload of a SetLocation (49) packet
+type SetLocationMessage struct {
+	Location  [16]byte
+	Label     [32]byte
+	UpdatedAt uint64
+}
+
+func (*SetLocationMessage) Type() PacketType { return SetLocation }
+
+func (*SetLocationMessage) Size() int { return 56 }
+
+func (m *SetLocationMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 56)
+	copy(p[0:16], m.Location[:])
+	copy(p[16:48], m.Label[:])
+	binary.LittleEndian.PutUint64(p[48:56], m.UpdatedAt)
+	return b, nil
+}
+
+func (m *SetLocationMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 56))
+}
+
+func (m *SetLocationMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 56 {
+		return errShortPayload(SetLocation, 56, len(data))
+	}
+	copy(m.Location[:], data[0:16])
+	copy(m.Label[:], data[16:48])
+	m.UpdatedAt = binary.LittleEndian.Uint64(data[48:56])
+	return nil
+}
+
+// StateLocationMessage is the payload of a StateLocation (50) packet
+type StateLocationMessage struct {
+	Location  [16]byte
+	Label     [32]byte
+	UpdatedAt uint64
+}
+
+func (*StateLocationMessage) Type() PacketType { return StateLocation }
+
+func (*StateLocationMessage) Size() int { return 56 }
+
+func (m *StateLocationMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 56)
+	copy(p[0:16], m.Location[:])
+	copy(p[16:48], m.Label[:])
+	binary.LittleEndian.PutUint64(p[48:56], m.UpdatedAt)
+	return b, nil
+}
+
+func (m *StateLocationMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 56))
+}
+
+func (m *StateLocationMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 56 {
+		return errShortPayload(StateLocation, 56, len(data))
+	}
+	copy(m.Location[:], data[0:16])
+	copy(m.Label[:], data[16:48])
+	m.UpdatedAt = binary.LittleEndian.Uint64(data[48:56])
+	return nil
+}
+
+// GetGroupMessage is the payload of a GetGroup (51) packet
+type GetGroupMessage struct{}
+
+func (*GetGroupMessage) Type() PacketType { return GetGroup }
+
+func (*GetGroupMessage) Size() int { return 0 }
+
+func (*GetGroupMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetGroupMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetGroupMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// SetGroupMessage is the payload of a SetGroup (52) packet
+type SetGroupMessage struct {
+	Group     [16]byte
+	Label     [32]byte
+	UpdatedAt uint64
+}
+
+func (*SetGroupMessage) Type() PacketType { return SetGroup }
+
+func (*SetGroupMessage) Size() int { return 56 }
+
+func (m *SetGroupMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 56)
+	copy(p[0:16], m.Group[:])
+	copy(p[16:48], m.Label[:])
+	binary.LittleEndian.PutUint64(p[48:56], m.UpdatedAt)
+	return b, nil
+}
+
+func (m *SetGroupMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 56))
+}
+
+func (m *SetGroupMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 56 {
+		return errShortPayload(SetGroup, 56, len(data))
+	}
+	copy(m.Group[:], data[0:16])
+	copy(m.Label[:], data[16:48])
+	m.UpdatedAt = binary.LittleEndian.Uint64(data[48:56])
+	return nil
+}
+
+// StateGroupMessage is the payload of a StateGroup (53) packet
+type StateGroupMessage struct {
+	Group     [16]byte
+	Label     [32]byte
+	UpdatedAt uint64
+}
+
+func (*StateGroupMessage) Type() PacketType { return StateGroup }
+
+func (*StateGroupMessage) Size() int { return 56 }
+
+func (m *StateGroupMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 56)
+	copy(p[0:16], m.Group[:])
+	copy(p[16:48], m.Label[:])
+	binary.LittleEndian.PutUint64(p[48:56], m.UpdatedAt)
+	return b, nil
+}
+
+func (m *StateGroupMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 56))
+}
+
+func (m *StateGroupMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 56 {
+		return errShortPayload(StateGroup, 56, len(data))
+	}
+	copy(m.Group[:], data[0:16])
+	copy(m.Label[:], data[16:48])
+	m.UpdatedAt = binary.LittleEndian.Uint64(data[48:56])
+	return nil
+}
+
+// EchoRequestMessage is the payload of a EchoRequest (58) packet
+type EchoRequestMessage struct {
+	Echoing [64]byte
+}
+
+func (*EchoRequestMessage) Type() PacketType { return EchoRequest }
+
+func (*EchoRequestMessage) Size() int { return 64 }
+
+func (m *EchoRequestMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 64)
+	copy(p[0:64], m.Echoing[:])
+	return b, nil
+}
+
+func (m *EchoRequestMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 64))
+}
+
+func (m *EchoRequestMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 64 {
+		return errShortPayload(EchoRequest, 64, len(data))
+	}
+	copy(m.Echoing[:], data[0:64])
+	return nil
+}
+
+// EchoResponseMessage is the payload of a EchoResponse (59) packet
+type EchoResponseMessage struct {
+	Echoing [64]byte
+}
+
+func (*EchoResponseMessage) Type() PacketType { return EchoResponse }
+
+func (*EchoResponseMessage) Size() int { return 64 }
+
+func (m *EchoResponseMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 64)
+	copy(p[0:64], m.Echoing[:])
+	return b, nil
+}
+
+func (m *EchoResponseMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 64))
+}
+
+func (m *EchoResponseMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 64 {
+		return errShortPayload(EchoResponse, 64, len(data))
+	}
+	copy(m.Echoing[:], data[0:64])
+	return nil
+}
+
+// GetColorMessage is the payload of a GetColor (101) packet
+type GetColorMessage struct{}
+
+func (*GetColorMessage) Type() PacketType { return GetColor }
+
+func (*GetColorMessage) Size() int { return 0 }
+
+func (*GetColorMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetColorMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetColorMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// SetColorMessage is the payload of a SetColor (102) packet
+type SetColorMessage struct {
+	Color    LIFXColor
+	Duration uint32
+}
+
+func (*SetColorMessage) Type() PacketType { return SetColor }
+
+func (*SetColorMessage) Size() int { return 13 }
+
+func (m *SetColorMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 13)
+	putColor(p[1:9], m.Color)
+	binary.LittleEndian.PutUint32(p[9:13], m.Duration)
+	return b, nil
+}
+
+func (m *SetColorMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 13))
+}
+
+func (m *SetColorMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 13 {
+		return errShortPayload(SetColor, 13, len(data))
+	}
+	m.Color = getColor(data[1:9])
+	m.Duration = binary.LittleEndian.Uint32(data[9:13])
+	return nil
+}
+
+// SetWaveformMessage is the payload of a SetWaveform (103) packet
+type SetWaveformMessage struct {
+	Transient bool
+	Color     LIFXColor
+	Period    uint32
+	Cycles    float32
+	SkewRatio int16
+	Waveform  LightWaveform
+}
+
+func (*SetWaveformMessage) Type() PacketType { return SetWaveform }
+
+func (*SetWaveformMessage) Size() int { return 21 }
+
+func (m *SetWaveformMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 21)
+	p[1] = boolByte(m.Transient)
+	putColor(p[2:10], m.Color)
+	binary.LittleEndian.PutUint32(p[10:14], m.Period)
+	binary.LittleEndian.PutUint32(p[14:18], math.Float32bits(m.Cycles))
+	binary.LittleEndian.PutUint16(p[18:20], uint16(m.SkewRatio))
+	p[20] = uint8(m.Waveform)
+	return b, nil
+}
+
+func (m *SetWaveformMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 21))
+}
+
+func (m *SetWaveformMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 21 {
+		return errShortPayload(SetWaveform, 21, len(data))
+	}
+	m.Transient = data[1] != 0
+	m.Color = getColor(data[2:10])
+	m.Period = binary.LittleEndian.Uint32(data[10:14])
+	m.Cycles = math.Float32frombits(binary.LittleEndian.Uint32(data[14:18]))
+	m.SkewRatio = int16(binary.LittleEndian.Uint16(data[18:20]))
+	m.Waveform = LightWaveform(data[20])
+	return nil
+}
+
+// LightStateMessage is the payload of a LightState (107) packet
+type LightStateMessage struct {
+	Color LIFXColor
+	Power uint16
+	Label [32]byte
+}
+
+func (*LightStateMessage) Type() PacketType { return LightState }
+
+func (*LightStateMessage) Size() int { return 52 }
+
+func (m *LightStateMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 52)
+	putColor(p[0:8], m.Color)
+	binary.LittleEndian.PutUint16(p[10:12], m.Power)
+	copy(p[12:44], m.Label[:])
+	return b, nil
+}
+
+func (m *LightStateMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 52))
+}
+
+func (m *LightStateMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 52 {
+		return errShortPayload(LightState, 52, len(data))
+	}
+	m.Color = getColor(data[0:8])
+	m.Power = binary.LittleEndian.Uint16(data[10:12])
+	copy(m.Label[:], data[12:44])
+	return nil
+}
+
+// GetLightPowerMessage is the payload of a GetLightPower (116) packet
+type GetLightPowerMessage struct{}
+
+func (*GetLightPowerMessage) Type() PacketType { return GetLightPower }
+
+func (*GetLightPowerMessage) Size() int { return 0 }
+
+func (*GetLightPowerMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetLightPowerMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetLightPowerMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// SetLightPowerMessage is the payload of a SetLightPower (117) packet
+type SetLightPowerMessage struct {
+	Level    uint16
+	Duration uint32
+}
+
+func (*SetLightPowerMessage) Type() PacketType { return SetLightPower }
+
+func (*SetLightPowerMessage) Size() int { return 6 }
+
+func (m *SetLightPowerMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 6)
+	binary.LittleEndian.PutUint16(p[0:2], m.Level)
+	binary.LittleEndian.PutUint32(p[2:6], m.Duration)
+	return b, nil
+}
+
+func (m *SetLightPowerMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 6))
+}
+
+func (m *SetLightPowerMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 6 {
+		return errShortPayload(SetLightPower, 6, len(data))
+	}
+	m.Level = binary.LittleEndian.Uint16(data[0:2])
+	m.Duration = binary.LittleEndian.Uint32(data[2:6])
+	return nil
+}
+
+// StateLightPowerMessage is the payload of a StateLightPower (118) packet
+type StateLightPowerMessage struct {
+	Level uint16
+}
+
+func (*StateLightPowerMessage) Type() PacketType { return StateLightPower }
+
+func (*StateLightPowerMessage) Size() int { return 2 }
+
+func (m *StateLightPowerMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 2)
+	binary.LittleEndian.PutUint16(p[0:2], m.Level)
+	return b, nil
+}
+
+func (m *StateLightPowerMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 2))
+}
+
+func (m *StateLightPowerMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errShortPayload(StateLightPower, 2, len(data))
+	}
+	m.Level = binary.LittleEndian.Uint16(data[0:2])
+	return nil
+}
+
+// SetWaveformOptionalMessage is the payload of a SetWaveformOptional (119) packet
+type SetWaveformOptionalMessage struct {
+	Transient     bool
+	Color         LIFXColor
+	Period        uint32
+	Cycles        float32
+	SkewRatio     int16
+	Waveform      LightWaveform
+	SetHue        bool
+	SetSaturation bool
+	SetBrightness bool
+	SetKelvin     bool
+}
+
+func (*SetWaveformOptionalMessage) Type() PacketType { return SetWaveformOptional }
+
+func (*SetWaveformOptionalMessage) Size() int { return 25 }
+
+func (m *SetWaveformOptionalMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 25)
+	p[1] = boolByte(m.Transient)
+	putColor(p[2:10], m.Color)
+	binary.LittleEndian.PutUint32(p[10:14], m.Period)
+	binary.LittleEndian.PutUint32(p[14:18], math.Float32bits(m.Cycles))
+	binary.LittleEndian.PutUint16(p[18:20], uint16(m.SkewRatio))
+	p[20] = uint8(m.Waveform)
+	p[21] = boolByte(m.SetHue)
+	p[22] = boolByte(m.SetSaturation)
+	p[23] = boolByte(m.SetBrightness)
+	p[24] = boolByte(m.SetKelvin)
+	return b, nil
+}
+
+func (m *SetWaveformOptionalMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 25))
+}
+
+func (m *SetWaveformOptionalMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 25 {
+		return errShortPayload(SetWaveformOptional, 25, len(data))
+	}
+	m.Transient = data[1] != 0
+	m.Color = getColor(data[2:10])
+	m.Period = binary.LittleEndian.Uint32(data[10:14])
+	m.Cycles = math.Float32frombits(binary.LittleEndian.Uint32(data[14:18]))
+	m.SkewRatio = int16(binary.LittleEndian.Uint16(data[18:20]))
+	m.Waveform = LightWaveform(data[20])
+	m.SetHue = data[21] != 0
+	m.SetSaturation = data[22] != 0
+	m.SetBrightness = data[23] != 0
+	m.SetKelvin = data[24] != 0
+	return nil
+}
+
+// GetInfraredMessage is the payload of a GetInfrared (120) packet
+type GetInfraredMessage struct{}
+
+func (*GetInfraredMessage) Type() PacketType { return GetInfrared }
+
+func (*GetInfraredMessage) Size() int { return 0 }
+
+func (*GetInfraredMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetInfraredMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetInfraredMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// StateInfraredMessage is the payload of a StateInfrared (121) packet
+type StateInfraredMessage struct {
+	Brightness uint16
+}
+
+func (*StateInfraredMessage) Type() PacketType { return StateInfrared }
+
+func (*StateInfraredMessage) Size() int { return 2 }
+
+func (m *StateInfraredMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 2)
+	binary.LittleEndian.PutUint16(p[0:2], m.Brightness)
+	return b, nil
+}
+
+func (m *StateInfraredMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 2))
+}
+
+func (m *StateInfraredMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errShortPayload(StateInfrared, 2, len(data))
+	}
+	m.Brightness = binary.LittleEndian.Uint16(data[0:2])
+	return nil
+}
+
+// SetInfraredMessage is the payload of a SetInfrared (122) packet
+type SetInfraredMessage struct {
+	Brightness uint16
+}
+
+func (*SetInfraredMessage) Type() PacketType { return SetInfrared }
+
+func (*SetInfraredMessage) Size() int { return 2 }
+
+func (m *SetInfraredMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 2)
+	binary.LittleEndian.PutUint16(p[0:2], m.Brightness)
+	return b, nil
+}
+
+func (m *SetInfraredMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 2))
+}
+
+func (m *SetInfraredMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errShortPayload(SetInfrared, 2, len(data))
+	}
+	m.Brightness = binary.LittleEndian.Uint16(data[0:2])
+	return nil
+}
+
+// GetHevCycleMessage is the payload of a GetHevCycle (142) packet
+type GetHevCycleMessage struct{}
+
+func (*GetHevCycleMessage) Type() PacketType { return GetHevCycle }
+
+func (*GetHevCycleMessage) Size() int { return 0 }
+
+func (*GetHevCycleMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetHevCycleMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetHevCycleMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// SetHevCycleMessage is the payload of a SetHevCycle (143) packet
+type SetHevCycleMessage struct {
+	Enable    bool
+	DurationS uint32
+}
+
+func (*SetHevCycleMessage) Type() PacketType { return SetHevCycle }
+
+func (*SetHevCycleMessage) Size() int { return 5 }
+
+func (m *SetHevCycleMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 5)
+	p[0] = boolByte(m.Enable)
+	binary.LittleEndian.PutUint32(p[1:5], m.DurationS)
+	return b, nil
+}
+
+func (m *SetHevCycleMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 5))
+}
+
+func (m *SetHevCycleMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 5 {
+		return errShortPayload(SetHevCycle, 5, len(data))
+	}
+	m.Enable = data[0] != 0
+	m.DurationS = binary.LittleEndian.Uint32(data[1:5])
+	return nil
+}
+
+// StateHevCycleMessage is the payload of a StateHevCycle (144) packet
+type StateHevCycleMessage struct {
+	DurationS  uint32
+	RemainingS uint32
+	LastPower  bool
+}
+
+func (*StateHevCycleMessage) Type() PacketType { return StateHevCycle }
+
+func (*StateHevCycleMessage) Size() int { return 9 }
+
+func (m *StateHevCycleMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 9)
+	binary.LittleEndian.PutUint32(p[0:4], m.DurationS)
+	binary.LittleEndian.PutUint32(p[4:8], m.RemainingS)
+	p[8] = boolByte(m.LastPower)
+	return b, nil
+}
+
+func (m *StateHevCycleMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 9))
+}
+
+func (m *StateHevCycleMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 9 {
+		return errShortPayload(StateHevCycle, 9, len(data))
+	}
+	m.DurationS = binary.LittleEndian.Uint32(data[0:4])
+	m.RemainingS = binary.LittleEndian.Uint32(data[4:8])
+	m.LastPower = data[8] != 0
+	return nil
+}
+
+// GetHevCycleConfigurationMessage is the payload of a GetHevCycleConfiguration (145) packet
+type GetHevCycleConfigurationMessage struct{}
+
+func (*GetHevCycleConfigurationMessage) Type() PacketType { return GetHevCycleConfiguration }
+
+func (*GetHevCycleConfigurationMessage) Size() int { return 0 }
+
+func (*GetHevCycleConfigurationMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetHevCycleConfigurationMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetHevCycleConfigurationMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// SetHevCycleConfigurationMessage is the payload of a SetHevCycleConfiguration (146) packet
+type SetHevCycleConfigurationMessage struct {
+	Indication bool
+	DurationS  uint32
+}
+
+func (*SetHevCycleConfigurationMessage) Type() PacketType { return SetHevCycleConfiguration }
+
+func (*SetHevCycleConfigurationMessage) Size() int { return 5 }
+
+func (m *SetHevCycleConfigurationMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 5)
+	p[0] = boolByte(m.Indication)
+	binary.LittleEndian.PutUint32(p[1:5], m.DurationS)
+	return b, nil
+}
+
+func (m *SetHevCycleConfigurationMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 5))
+}
+
+func (m *SetHevCycleConfigurationMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 5 {
+		return errShortPayload(SetHevCycleConfiguration, 5, len(data))
+	}
+	m.Indication = data[0] != 0
+	m.DurationS = binary.LittleEndian.Uint32(data[1:5])
+	return nil
+}
+
+// StateHevCycleConfigurationMessage is the payload of a StateHevCycleConfiguration (147) packet
+type StateHevCycleConfigurationMessage struct {
+	Indication bool
+	DurationS  uint32
+}
+
+func (*StateHevCycleConfigurationMessage) Type() PacketType { return StateHevCycleConfiguration }
+
+func (*StateHevCycleConfigurationMessage) Size() int { return 5 }
+
+func (m *StateHevCycleConfigurationMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 5)
+	p[0] = boolByte(m.Indication)
+	binary.LittleEndian.PutUint32(p[1:5], m.DurationS)
+	return b, nil
+}
+
+func (m *StateHevCycleConfigurationMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 5))
+}
+
+func (m *StateHevCycleConfigurationMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 5 {
+		return errShortPayload(StateHevCycleConfiguration, 5, len(data))
+	}
+	m.Indication = data[0] != 0
+	m.DurationS = binary.LittleEndian.Uint32(data[1:5])
+	return nil
+}
+
+// GetLastHevCycleResultMessage is the payload of a GetLastHevCycleResult (148) packet
+type GetLastHevCycleResultMessage struct{}
+
+func (*GetLastHevCycleResultMessage) Type() PacketType { return GetLastHevCycleResult }
+
+func (*GetLastHevCycleResultMessage) Size() int { return 0 }
+
+func (*GetLastHevCycleResultMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetLastHevCycleResultMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetLastHevCycleResultMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// StateLastHevCycleResultMessage is the payload of a StateLastHevCycleResult (149) packet
+type StateLastHevCycleResultMessage struct {
+	Result LightLastHevCycleResult
+}
+
+func (*StateLastHevCycleResultMessage) Type() PacketType { return StateLastHevCycleResult }
+
+func (*StateLastHevCycleResultMessage) Size() int { return 1 }
+
+func (m *StateLastHevCycleResultMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 1)
+	p[0] = uint8(m.Result)
+	return b, nil
+}
+
+func (m *StateLastHevCycleResultMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 1))
+}
+
+func (m *StateLastHevCycleResultMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return errShortPayload(StateLastHevCycleResult, 1, len(data))
+	}
+	m.Result = LightLastHevCycleResult(data[0])
+	return nil
+}
+
+// StateUnhandledMessage is the payload of a StateUnhandled (223) packet
+type StateUnhandledMessage struct {
+	UnhandledType uint16
+}
+
+func (*StateUnhandledMessage) Type() PacketType { return StateUnhandled }
+
+func (*StateUnhandledMessage) Size() int { return 2 }
+
+func (m *StateUnhandledMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 2)
+	binary.LittleEndian.PutUint16(p[0:2], m.UnhandledType)
+	return b, nil
+}
+
+func (m *StateUnhandledMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 2))
+}
+
+func (m *StateUnhandledMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errShortPayload(StateUnhandled, 2, len(data))
+	}
+	m.UnhandledType = binary.LittleEndian.Uint16(data[0:2])
+	return nil
+}
+
+// SensorGetAmbientLightMessage is the payload of a SensorGetAmbientLight (401) packet
+type SensorGetAmbientLightMessage struct{}
+
+func (*SensorGetAmbientLightMessage) Type() PacketType { return SensorGetAmbientLight }
+
+func (*SensorGetAmbientLightMessage) Size() int { return 0 }
+
+func (*SensorGetAmbientLightMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *SensorGetAmbientLightMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*SensorGetAmbientLightMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// StateAmbientLightMessage is the payload of a StateAmbientLight (402) packet
+type StateAmbientLightMessage struct {
+	Lux float32
+}
+
+func (*StateAmbientLightMessage) Type() PacketType { return StateAmbientLight }
+
+func (*StateAmbientLightMessage) Size() int { return 4 }
+
+func (m *StateAmbientLightMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 4)
+	binary.LittleEndian.PutUint32(p[0:4], math.Float32bits(m.Lux))
+	return b, nil
+}
+
+func (m *StateAmbientLightMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 4))
+}
+
+func (m *StateAmbientLightMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errShortPayload(StateAmbientLight, 4, len(data))
+	}
+	m.Lux = math.Float32frombits(binary.LittleEndian.Uint32(data[0:4]))
+	return nil
+}
+
+// SetColorZonesMessage is the payload of a SetColorZones (501) packet
+type SetColorZonesMessage struct {
+	StartIndex uint8
+	EndIndex   uint8
+	Color      LIFXColor
+	Duration   uint32
+	Apply      MultiZoneApplicationRequest
+}
+
+func (*SetColorZonesMessage) Type() PacketType { return SetColorZones }
+
+func (*SetColorZonesMessage) Size() int { return 15 }
+
+func (m *SetColorZonesMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 15)
+	p[0] = m.StartIndex
+	p[1] = m.EndIndex
+	putColor(p[2:10], m.Color)
+	binary.LittleEndian.PutUint32(p[10:14], m.Duration)
+	p[14] = uint8(m.Apply)
+	return b, nil
+}
+
+func (m *SetColorZonesMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 15))
+}
+
+func (m *SetColorZonesMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 15 {
+		return errShortPayload(SetColorZones, 15, len(data))
+	}
+	m.StartIndex = data[0]
+	m.EndIndex = data[1]
+	m.Color = getColor(data[2:10])
+	m.Duration = binary.LittleEndian.Uint32(data[10:14])
+	m.Apply = MultiZoneApplicationRequest(data[14])
+	return nil
+}
+
+// GetColorZonesMessage is the payload of a GetColorZones (502) packet
+type GetColorZonesMessage struct {
+	StartIndex uint8
+	EndIndex   uint8
+}
+
+func (*GetColorZonesMessage) Type() PacketType { return GetColorZones }
+
+func (*GetColorZonesMessage) Size() int { return 2 }
+
+func (m *GetColorZonesMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 2)
+	p[0] = m.StartIndex
+	p[1] = m.EndIndex
+	return b, nil
+}
+
+func (m *GetColorZonesMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 2))
+}
+
+func (m *GetColorZonesMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errShortPayload(GetColorZones, 2, len(data))
+	}
+	m.StartIndex = data[0]
+	m.EndIndex = data[1]
+	return nil
+}
+
+// StateZoneMessage is the payload of a StateZone (503) packet
+type StateZoneMessage struct {
+	Count uint8
+	Index uint8
+	Color LIFXColor
+}
+
+func (*StateZoneMessage) Type() PacketType { return StateZone }
+
+func (*StateZoneMessage) Size() int { return 10 }
+
+func (m *StateZoneMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 10)
+	p[0] = m.Count
+	p[1] = m.Index
+	putColor(p[2:10], m.Color)
+	return b, nil
+}
+
+func (m *StateZoneMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 10))
+}
+
+func (m *StateZoneMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 10 {
+		return errShortPayload(StateZone, 10, len(data))
+	}
+	m.Count = data[0]
+	m.Index = data[1]
+	m.Color = getColor(data[2:10])
+	return nil
+}
+
+// StateMultiZoneMessage is the payload of a StateMultiZone (506) packet
+type StateMultiZoneMessage struct {
+	Count  uint8
+	Index  uint8
+	Colors [8]LIFXColor
+}
+
+func (*StateMultiZoneMessage) Type() PacketType { return StateMultiZone }
+
+func (*StateMultiZoneMessage) Size() int { return 66 }
+
+func (m *StateMultiZoneMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 66)
+	p[0] = m.Count
+	p[1] = m.Index
+	for i := range m.Colors {
+		putColor(p[2+i*8:], m.Colors[i])
+	}
+	return b, nil
+}
+
+func (m *StateMultiZoneMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 66))
+}
+
+func (m *StateMultiZoneMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 66 {
+		return errShortPayload(StateMultiZone, 66, len(data))
+	}
+	m.Count = data[0]
+	m.Index = data[1]
+	for i := range m.Colors {
+		m.Colors[i] = getColor(data[2+i*8:])
+	}
+	return nil
+}
+
+// GetMultiZoneEffectMessage is the payload of a GetMultiZoneEffect (507) packet
+type GetMultiZoneEffectMessage struct{}
+
+func (*GetMultiZoneEffectMessage) Type() PacketType { return GetMultiZoneEffect }
+
+func (*GetMultiZoneEffectMessage) Size() int { return 0 }
+
+func (*GetMultiZoneEffectMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetMultiZoneEffectMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetMultiZoneEffectMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// SetMultiZoneEffectMessage is the payload of a SetMultiZoneEffect (508) packet
+type SetMultiZoneEffectMessage struct {
+	Settings MultiZoneEffectSettings
+}
+
+func (*SetMultiZoneEffectMessage) Type() PacketType { return SetMultiZoneEffect }
+
+func (*SetMultiZoneEffectMessage) Size() int { return 59 }
+
+func (m *SetMultiZoneEffectMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 59)
+	m.Settings.encode(p[0:59])
+	return b, nil
+}
+
+func (m *SetMultiZoneEffectMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 59))
+}
+
+func (m *SetMultiZoneEffectMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 59 {
+		return errShortPayload(SetMultiZoneEffect, 59, len(data))
+	}
+	m.Settings.decode(data[0:59])
+	return nil
+}
+
+// StateMultiZoneEffectMessage is the payload of a StateMultiZoneEffect (509) packet
+type StateMultiZoneEffectMessage struct {
+	Settings MultiZoneEffectSettings
+}
+
+func (*StateMultiZoneEffectMessage) Type() PacketType { return StateMultiZoneEffect }
+
+func (*StateMultiZoneEffectMessage) Size() int { return 59 }
+
+func (m *StateMultiZoneEffectMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 59)
+	m.Settings.encode(p[0:59])
+	return b, nil
+}
+
+func (m *StateMultiZoneEffectMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 59))
+}
+
+func (m *StateMultiZoneEffectMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 59 {
+		return errShortPayload(StateMultiZoneEffect, 59, len(data))
+	}
+	m.Settings.decode(data[0:59])
+	return nil
+}
+
+// SetExtendedColorZonesMessage is the payload of a SetExtendedColorZones (510) packet
+type SetExtendedColorZonesMessage struct {
+	Duration    uint32
+	Apply       MultiZoneExtendedApplicationRequest
+	Index       uint16
+	ColorsCount uint8
+	Colors      [82]LIFXColor
+}
+
+func (*SetExtendedColorZonesMessage) Type() PacketType { return SetExtendedColorZones }
+
+func (*SetExtendedColorZonesMessage) Size() int { return 664 }
+
+func (m *SetExtendedColorZonesMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 664)
+	binary.LittleEndian.PutUint32(p[0:4], m.Duration)
+	p[4] = uint8(m.Apply)
+	binary.LittleEndian.PutUint16(p[5:7], m.Index)
+	p[7] = m.ColorsCount
+	for i := range m.Colors {
+		putColor(p[8+i*8:], m.Colors[i])
+	}
+	return b, nil
+}
+
+func (m *SetExtendedColorZonesMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 664))
+}
+
+func (m *SetExtendedColorZonesMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 664 {
+		return errShortPayload(SetExtendedColorZones, 664, len(data))
+	}
+	m.Duration = binary.LittleEndian.Uint32(data[0:4])
+	m.Apply = MultiZoneExtendedApplicationRequest(data[4])
+	m.Index = binary.LittleEndian.Uint16(data[5:7])
+	m.ColorsCount = data[7]
+	for i := range m.Colors {
+		m.Colors[i] = getColor(data[8+i*8:])
+	}
+	return nil
+}
+
+// GetExtendedColorZonesMessage is the payload of a GetExtendedColorZones (511) packet
+type GetExtendedColorZonesMessage struct{}
+
+func (*GetExtendedColorZonesMessage) Type() PacketType { return GetExtendedColorZones }
+
+func (*GetExtendedColorZonesMessage) Size() int { return 0 }
+
+func (*GetExtendedColorZonesMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetExtendedColorZonesMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetExtendedColorZonesMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// StateExtendedColorZonesMessage is the payload of a StateExtendedColorZones (512) packet
+type StateExtendedColorZonesMessage struct {
+	Count       uint16
+	Index       uint16
+	ColorsCount uint8
+	Colors      [82]LIFXColor
+}
+
+func (*StateExtendedColorZonesMessage) Type() PacketType { return StateExtendedColorZones }
+
+func (*StateExtendedColorZonesMessage) Size() int { return 661 }
+
+func (m *StateExtendedColorZonesMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 661)
+	binary.LittleEndian.PutUint16(p[0:2], m.Count)
+	binary.LittleEndian.PutUint16(p[2:4], m.Index)
+	p[4] = m.ColorsCount
+	for i := range m.Colors {
+		putColor(p[5+i*8:], m.Colors[i])
+	}
+	return b, nil
+}
+
+func (m *StateExtendedColorZonesMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 661))
+}
+
+func (m *StateExtendedColorZonesMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 661 {
+		return errShortPayload(StateExtendedColorZones, 661, len(data))
+	}
+	m.Count = binary.LittleEndian.Uint16(data[0:2])
+	m.Index = binary.LittleEndian.Uint16(data[2:4])
+	m.ColorsCount = data[4]
+	for i := range m.Colors {
+		m.Colors[i] = getColor(data[5+i*8:])
+	}
+	return nil
+}
+
+// GetDeviceChainMessage is the payload of a GetDeviceChain (701) packet
+type GetDeviceChainMessage struct{}
+
+func (*GetDeviceChainMessage) Type() PacketType { return GetDeviceChain }
+
+func (*GetDeviceChainMessage) Size() int { return 0 }
+
+func (*GetDeviceChainMessage) AppendBinary(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func (m *GetDeviceChainMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 0))
+}
+
+func (*GetDeviceChainMessage) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
+// StateDeviceChainMessage is the payload of a StateDeviceChain (702) packet
+type StateDeviceChainMessage struct {
+	StartIndex       uint8
+	TileDevices      [16]TileStateDevice
+	TileDevicesCount uint8
+}
+
+func (*StateDeviceChainMessage) Type() PacketType { return StateDeviceChain }
+
+func (*StateDeviceChainMessage) Size() int { return 882 }
+
+func (m *StateDeviceChainMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 882)
+	p[0] = m.StartIndex
+	for i := range m.TileDevices {
+		m.TileDevices[i].encode(p[1+i*55:])
+	}
+	p[881] = m.TileDevicesCount
+	return b, nil
+}
+
+func (m *StateDeviceChainMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 882))
+}
+
+func (m *StateDeviceChainMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 882 {
+		return errShortPayload(StateDeviceChain, 882, len(data))
+	}
+	m.StartIndex = data[0]
+	for i := range m.TileDevices {
+		m.TileDevices[i].decode(data[1+i*55:])
+	}
+	m.TileDevicesCount = data[881]
+	return nil
+}
+
+// SetUserPositionMessage is the payload of a SetUserPosition (703) packet
+type SetUserPositionMessage struct {
+	TileIndex uint8
+	UserX     float32
+	UserY     float32
+}
+
+func (*SetUserPositionMessage) Type() PacketType { return SetUserPosition }
+
+func (*SetUserPositionMessage) Size() int { return 11 }
+
+func (m *SetUserPositionMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 11)
+	p[0] = m.TileIndex
+	binary.LittleEndian.PutUint32(p[3:7], math.Float32bits(m.UserX))
+	binary.LittleEndian.PutUint32(p[7:11], math.Float32bits(m.UserY))
+	return b, nil
+}
+
+func (m *SetUserPositionMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 11))
+}
+
+func (m *SetUserPositionMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 11 {
+		return errShortPayload(SetUserPosition, 11, len(data))
+	}
+	m.TileIndex = data[0]
+	m.UserX = math.Float32frombits(binary.LittleEndian.Uint32(data[3:7]))
+	m.UserY = math.Float32frombits(binary.LittleEndian.Uint32(data[7:11]))
+	return nil
+}
+
+// Get64Message is the payload of a Get64 (707) packet
+type Get64Message struct {
+	TileIndex uint8
+	Length    uint8
+	Rect      TileBufferRect
+}
+
+func (*Get64Message) Type() PacketType { return Get64 }
+
+func (*Get64Message) Size() int { return 6 }
+
+func (m *Get64Message) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 6)
+	p[0] = m.TileIndex
+	p[1] = m.Length
+	m.Rect.encode(p[2:6])
+	return b, nil
+}
+
+func (m *Get64Message) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 6))
+}
+
+func (m *Get64Message) UnmarshalBinary(data []byte) error {
+	if len(data) < 6 {
+		return errShortPayload(Get64, 6, len(data))
+	}
+	m.TileIndex = data[0]
+	m.Length = data[1]
+	m.Rect.decode(data[2:6])
+	return nil
+}
+
+// State64Message is the payload of a State64 (711) packet
+type State64Message struct {
+	TileIndex uint8
+	Rect      TileBufferRect
+	Colors    [64]LIFXColor
+}
+
+func (*State64Message) Type() PacketType { return State64 }
+
+func (*State64Message) Size() int { return 517 }
+
+func (m *State64Message) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 517)
+	p[0] = m.TileIndex
+	m.Rect.encode(p[1:5])
+	for i := range m.Colors {
+		putColor(p[5+i*8:], m.Colors[i])
+	}
+	return b, nil
+}
+
+func (m *State64Message) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 517))
+}
+
+func (m *State64Message) UnmarshalBinary(data []byte) error {
+	if len(data) < 517 {
+		return errShortPayload(State64, 517, len(data))
+	}
+	m.TileIndex = data[0]
+	m.Rect.decode(data[1:5])
+	for i := range m.Colors {
+		m.Colors[i] = getColor(data[5+i*8:])
+	}
+	return nil
+}
+
+// Set64Message is the payload of a Set64 (715) packet
+type Set64Message struct {
+	TileIndex uint8
+	Length    uint8
+	Rect      TileBufferRect
+	Duration  uint32
+	Colors    [64]LIFXColor
+}
+
+func (*Set64Message) Type() PacketType { return Set64 }
+
+func (*Set64Message) Size() int { return 522 }
+
+func (m *Set64Message) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 522)
+	p[0] = m.TileIndex
+	p[1] = m.Length
+	m.Rect.encode(p[2:6])
+	binary.LittleEndian.PutUint32(p[6:10], m.Duration)
+	for i := range m.Colors {
+		putColor(p[10+i*8:], m.Colors[i])
+	}
+	return b, nil
+}
+
+func (m *Set64Message) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 522))
+}
+
+func (m *Set64Message) UnmarshalBinary(data []byte) error {
+	if len(data) < 522 {
+		return errShortPayload(Set64, 522, len(data))
+	}
+	m.TileIndex = data[0]
+	m.Length = data[1]
+	m.Rect.decode(data[2:6])
+	m.Duration = binary.LittleEndian.Uint32(data[6:10])
+	for i := range m.Colors {
+		m.Colors[i] = getColor(data[10+i*8:])
+	}
+	return nil
+}
+
+// GetTileEffectMessage is the payload of a GetTileEffect (718) packet
+type GetTileEffectMessage struct{}
+
+func (*GetTileEffectMessage) Type() PacketType { return GetTileEffect }
+
+func (*GetTileEffectMessage) Size() int { return 2 }
+
+func (*GetTileEffectMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, _ = grow(b, 2)
+	return b, nil
+}
+
+func (m *GetTileEffectMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 2))
+}
+
+func (*GetTileEffectMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errShortPayload(GetTileEffect, 2, len(data))
+	}
+	return nil
+}
+
+// SetTileEffectMessage is the payload of a SetTileEffect (719) packet
+type SetTileEffectMessage struct {
+	Settings TileEffectSettings
+}
+
+func (*SetTileEffectMessage) Type() PacketType { return SetTileEffect }
+
+func (*SetTileEffectMessage) Size() int { return 190 }
+
+func (m *SetTileEffectMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 190)
+	m.Settings.encode(p[2:190])
+	return b, nil
+}
+
+func (m *SetTileEffectMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 190))
+}
+
+func (m *SetTileEffectMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 190 {
+		return errShortPayload(SetTileEffect, 190, len(data))
+	}
+	m.Settings.decode(data[2:190])
+	return nil
+}
+
+// StateTileEffectMessage is the payload of a StateTileEffect (720) packet
+type StateTileEffectMessage struct {
+	Settings TileEffectSettings
+}
+
+func (*StateTileEffectMessage) Type() PacketType { return StateTileEffect }
+
+func (*StateTileEffectMessage) Size() int { return 189 }
+
+func (m *StateTileEffectMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 189)
+	m.Settings.encode(p[1:189])
+	return b, nil
+}
+
+func (m *StateTileEffectMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 189))
+}
+
+func (m *StateTileEffectMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 189 {
+		return errShortPayload(StateTileEffect, 189, len(data))
+	}
+	m.Settings.decode(data[1:189])
+	return nil
+}
+
+// GetRPowerMessage is the payload of a GetRPower (816) packet
+type GetRPowerMessage struct {
+	RelayIndex uint8
+}
+
+func (*GetRPowerMessage) Type() PacketType { return GetRPower }
+
+func (*GetRPowerMessage) Size() int { return 1 }
+
+func (m *GetRPowerMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 1)
+	p[0] = m.RelayIndex
+	return b, nil
+}
+
+func (m *GetRPowerMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 1))
+}
+
+func (m *GetRPowerMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return errShortPayload(GetRPower, 1, len(data))
+	}
+	m.RelayIndex = data[0]
+	return nil
+}
+
+// SetRPowerMessage is the payload of a SetRPower (817) packet
+type SetRPowerMessage struct {
+	RelayIndex uint8
+	Level      uint16
+}
+
+func (*SetRPowerMessage) Type() PacketType { return SetRPower }
+
+func (*SetRPowerMessage) Size() int { return 3 }
+
+func (m *SetRPowerMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 3)
+	p[0] = m.RelayIndex
+	binary.LittleEndian.PutUint16(p[1:3], m.Level)
+	return b, nil
+}
+
+func (m *SetRPowerMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 3))
+}
+
+func (m *SetRPowerMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 3 {
+		return errShortPayload(SetRPower, 3, len(data))
+	}
+	m.RelayIndex = data[0]
+	m.Level = binary.LittleEndian.Uint16(data[1:3])
+	return nil
+}
+
+// StateRPowerMessage is the payload of a StateRPower (818) packet
+type StateRPowerMessage struct {
+	RelayIndex uint8
+	Level      uint16
+}
+
+func (*StateRPowerMessage) Type() PacketType { return StateRPower }
+
+func (*StateRPowerMessage) Size() int { return 3 }
+
+func (m *StateRPowerMessage) AppendBinary(b []byte) ([]byte, error) {
+	b, p := grow(b, 3)
+	p[0] = m.RelayIndex
+	binary.LittleEndian.PutUint16(p[1:3], m.Level)
+	return b, nil
+}
+
+func (m *StateRPowerMessage) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 3))
+}
+
+func (m *StateRPowerMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < 3 {
+		return errShortPayload(StateRPower, 3, len(data))
+	}
+	m.RelayIndex = data[0]
+	m.Level = binary.LittleEndian.Uint16(data[1:3])
+	return nil
+}
+
+// messageTypes creates an empty message for each packet type, see RegisterMessage
+var messageTypes = map[PacketType]func() Message{
+	GetService:                 func() Message { return new(GetServiceMessage) },
+	StateService:               func() Message { return new(StateServiceMessage) },
+	GetHostFirmware:            func() Message { return new(GetHostFirmwareMessage) },
+	StateHostFirmware:          func() Message { return new(StateHostFirmwareMessage) },
+	GetWifiInfo:                func() Message { return new(GetWifiInfoMessage) },
+	StateWifiInfo:              func() Message { return new(StateWifiInfoMessage) },
+	GetWifiFirmware:            func() Message { return new(GetWifiFirmwareMessage) },
+	StateWifiFirmware:          func() Message { return new(StateWifiFirmwareMessage) },
+	GetPower:                   func() Message { return new(GetPowerMessage) },
+	SetPower:                   func() Message { return new(SetPowerMessage) },
+	StatePower:                 func() Message { return new(StatePowerMessage) },
+	GetLabel:                   func() Message { return new(GetLabelMessage) },
+	SetLabel:                   func() Message { return new(SetLabelMessage) },
+	StateLabel:                 func() Message { return new(StateLabelMessage) },
+	GetVersion:                 func() Message { return new(GetVersionMessage) },
+	StateVersion:               func() Message { return new(StateVersionMessage) },
+	GetInfo:                    func() Message { return new(GetInfoMessage) },
+	StateInfo:                  func() Message { return new(StateInfoMessage) },
+	SetReboot:                  func() Message { return new(SetRebootMessage) },
+	Acknowledgement:            func() Message { return new(AcknowledgementMessage) },
+	GetLocation:                func() Message { return new(GetLocationMessage) },
+	SetLocation:                func() Message { return new(SetLocationMessage) },
+	StateLocation:              func() Message { return new(StateLocationMessage) },
+	GetGroup:                   func() Message { return new(GetGroupMessage) },
+	SetGroup:                   func() Message { return new(SetGroupMessage) },
+	StateGroup:                 func() Message { return new(StateGroupMessage) },
+	EchoRequest:                func() Message { return new(EchoRequestMessage) },
+	EchoResponse:               func() Message { return new(EchoResponseMessage) },
+	GetColor:                   func() Message { return new(GetColorMessage) },
+	SetColor:                   func() Message { return new(SetColorMessage) },
+	SetWaveform:                func() Message { return new(SetWaveformMessage) },
+	LightState:                 func() Message { return new(LightStateMessage) },
+	GetLightPower:              func() Message { return new(GetLightPowerMessage) },
+	SetLightPower:              func() Message { return new(SetLightPowerMessage) },
+	StateLightPower:            func() Message { return new(StateLightPowerMessage) },
+	SetWaveformOptional:        func() Message { return new(SetWaveformOptionalMessage) },
+	GetInfrared:                func() Message { return new(GetInfraredMessage) },
+	StateInfrared:              func() Message { return new(StateInfraredMessage) },
+	SetInfrared:                func() Message { return new(SetInfraredMessage) },
+	GetHevCycle:                func() Message { return new(GetHevCycleMessage) },
+	SetHevCycle:                func() Message { return new(SetHevCycleMessage) },
+	StateHevCycle:              func() Message { return new(StateHevCycleMessage) },
+	GetHevCycleConfiguration:   func() Message { return new(GetHevCycleConfigurationMessage) },
+	SetHevCycleConfiguration:   func() Message { return new(SetHevCycleConfigurationMessage) },
+	StateHevCycleConfiguration: func() Message { return new(StateHevCycleConfigurationMessage) },
+	GetLastHevCycleResult:      func() Message { return new(GetLastHevCycleResultMessage) },
+	StateLastHevCycleResult:    func() Message { return new(StateLastHevCycleResultMessage) },
+	StateUnhandled:             func() Message { return new(StateUnhandledMessage) },
+	SensorGetAmbientLight:      func() Message { return new(SensorGetAmbientLightMessage) },
+	StateAmbientLight:          func() Message { return new(StateAmbientLightMessage) },
+	SetColorZones:              func() Message { return new(SetColorZonesMessage) },
+	GetColorZones:              func() Message { return new(GetColorZonesMessage) },
+	StateZone:                  func() Message { return new(StateZoneMessage) },
+	StateMultiZone:             func() Message { return new(StateMultiZoneMessage) },
+	GetMultiZoneEffect:         func() Message { return new(GetMultiZoneEffectMessage) },
+	SetMultiZoneEffect:         func() Message { return new(SetMultiZoneEffectMessage) },
+	StateMultiZoneEffect:       func() Message { return new(StateMultiZoneEffectMessage) },
+	SetExtendedColorZones:      func() Message { return new(SetExtendedColorZonesMessage) },
+	GetExtendedColorZones:      func() Message { return new(GetExtendedColorZonesMessage) },
+	StateExtendedColorZones:    func() Message { return new(StateExtendedColorZonesMessage) },
+	GetDeviceChain:             func() Message { return new(GetDeviceChainMessage) },
+	StateDeviceChain:           func() Message { return new(StateDeviceChainMessage) },
+	SetUserPosition:            func() Message { return new(SetUserPositionMessage) },
+	Get64:                      func() Message { return new(Get64Message) },
+	State64:                    func() Message { return new(State64Message) },
+	Set64:                      func() Message { return new(Set64Message) },
+	GetTileEffect:              func() Message { return new(GetTileEffectMessage) },
+	SetTileEffect:              func() Message { return new(SetTileEffectMessage) },
+	StateTileEffect:            func() Message { return new(StateTileEffectMessage) },
+	GetRPower:                  func() Message { return new(GetRPowerMessage) },
+	SetRPower:                  func() Message { return new(SetRPowerMessage) },
+	StateRPower:                func() Message { return new(StateRPowerMessage) },
+}
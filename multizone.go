@@ -0,0 +1,265 @@
+package lifxlan
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// maxLegacyZones is the number of zones the legacy messages can address with 8-bit indexes
+	maxLegacyZones = 256
+
+	// maxExtendedZones is the number of zones carried by a single extended multizone message
+	maxExtendedZones = 82
+)
+
+// hasMultizone reports whether a product is a multizone strip
+func hasMultizone(f Features) bool { return f.Multizone }
+
+// hasExtendedMultizone reports whether a product supports the extended multizone messages
+func hasExtendedMultizone(f Features) bool { return f.ExtendedMultizone }
+
+// zoneCollector assembles zone colors reported over several state packets
+type zoneCollector struct {
+	start, end int // Requested zone range, inclusive
+	zones      []LIFXColor
+	seen       []bool
+	remaining  int
+}
+
+// add stores colors reported from zone index onwards and returns true once
+// every requested zone has been received
+func (z *zoneCollector) add(count, index int, colors []LIFXColor) bool {
+	if z.zones == nil {
+		// The first response says how many zones the device has
+		n := max(min(z.end, count-1)-z.start+1, 0)
+		z.zones = make([]LIFXColor, n)
+		z.seen = make([]bool, n)
+		z.remaining = n
+	}
+
+	for i, color := range colors {
+		zone := index + i - z.start
+		if zone < 0 || zone >= len(z.zones) || z.seen[zone] {
+			continue // Outside the requested range or a duplicate
+		}
+
+		z.zones[zone] = color
+		z.seen[zone] = true
+		z.remaining--
+	}
+
+	return z.remaining == 0
+}
+
+// GetColorZones returns the colors of zones start to end inclusive, which the
+// device reports over several StateZone and StateMultiZone packets
+func (d *Device) GetColorZones(start, end uint8) ([]LIFXColor, error) {
+	if err := d.require("multizone", hasMultizone); err != nil {
+		return nil, err
+	}
+	if start > end {
+		return nil, fmt.Errorf("invalid zone range %d-%d", start, end)
+	}
+
+	// Each StateMultiZone carries 8 zones and may start before the range, make
+	// room for every packet of the range
+	queue := (int(end)-int(start))/8 + 2
+
+	zones := zoneCollector{start: int(start), end: int(end)}
+	err := d.collect(&GetColorZonesMessage{StartIndex: start, EndIndex: end}, queue, func(h *Header, payload []byte) (bool, error) {
+		switch h.Type() {
+		case StateZone:
+			var state StateZoneMessage
+			if err := state.UnmarshalBinary(payload); err != nil {
+				return false, err
+			}
+			return zones.add(int(state.Count), int(state.Index), []LIFXColor{state.Color}), nil
+
+		case StateMultiZone:
+			var state StateMultiZoneMessage
+			if err := state.UnmarshalBinary(payload); err != nil {
+				return false, err
+			}
+			return zones.add(int(state.Count), int(state.Index), state.Colors[:]), nil
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return zones.zones, nil
+}
+
+// SetColorZones sets zones start to end inclusive to a single color.
+//
+// With MultiZoneApplicationRequestNoApply the change is buffered until a later
+// request uses MultiZoneApplicationRequestApply, so several ranges can change at
+// once. MultiZoneApplicationRequestApplyOnly shows buffered changes and ignores
+// the range and color.
+func (d *Device) SetColorZones(start, end uint8, color LIFXColor, duration time.Duration, apply MultiZoneApplicationRequest) error {
+	if err := d.require("multizone", hasMultizone); err != nil {
+		return err
+	}
+
+	return d.send(&SetColorZonesMessage{
+		StartIndex: start,
+		EndIndex:   end,
+		Color:      color,
+		Duration:   uint32(duration.Milliseconds()),
+		Apply:      apply,
+	})
+}
+
+// GetExtendedColorZones returns the color of every zone using the extended
+// multizone messages, which report up to 82 zones per packet
+func (d *Device) GetExtendedColorZones() ([]LIFXColor, error) {
+	if err := d.require("extended multizone", hasExtendedMultizone); err != nil {
+		return nil, err
+	}
+
+	zones := zoneCollector{start: 0, end: 1<<16 - 1}
+	err := d.collect(&GetExtendedColorZonesMessage{}, waiterQueue, func(h *Header, payload []byte) (bool, error) {
+		if h.Type() != StateExtendedColorZones {
+			return false, nil
+		}
+
+		var state StateExtendedColorZonesMessage
+		if err := state.UnmarshalBinary(payload); err != nil {
+			return false, err
+		}
+
+		colors := state.Colors[:min(int(state.ColorsCount), maxExtendedZones)]
+		return zones.add(int(state.Count), int(state.Index), colors), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return zones.zones, nil
+}
+
+// SetExtendedColorZones sets up to 82 zones starting at index in a single packet,
+// see SetColorZones for the meaning of apply
+func (d *Device) SetExtendedColorZones(index uint16, colors []LIFXColor, duration time.Duration, apply MultiZoneExtendedApplicationRequest) error {
+	if err := d.require("extended multizone", hasExtendedMultizone); err != nil {
+		return err
+	}
+	if len(colors) > maxExtendedZones {
+		return fmt.Errorf("too many zones: %d, at most %d fit in one packet", len(colors), maxExtendedZones)
+	}
+
+	msg := SetExtendedColorZonesMessage{
+		Duration:    uint32(duration.Milliseconds()),
+		Apply:       apply,
+		Index:       index,
+		ColorsCount: uint8(len(colors)),
+	}
+	copy(msg.Colors[:], colors)
+
+	// Encode through the concrete type instead of send, which would move the
+	// message to the heap on every frame
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	packet := AppendHeader((*buf)[:0], d.client.identifier, d.mac, msg.Type(), uint16(msg.Size()))
+	packet, err := msg.AppendBinary(packet)
+	if err != nil {
+		return err
+	}
+
+	return d.Send(packet)
+}
+
+// GetZones returns the color of every zone of a multizone device, using the
+// extended messages when the product supports them
+func (d *Device) GetZones() ([]LIFXColor, error) {
+	features, err := d.Features()
+	if err != nil {
+		return nil, err
+	}
+
+	if features.ExtendedMultizone {
+		return d.GetExtendedColorZones()
+	}
+
+	return d.GetColorZones(0, maxLegacyZones-1)
+}
+
+// SetZones sets the color of each zone of a multizone device, starting at zone 0.
+// The extended messages are used when the product supports them.
+func (d *Device) SetZones(colors []LIFXColor, duration time.Duration) error {
+	features, err := d.Features()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case features.ExtendedMultizone:
+		return d.setExtendedZones(colors, duration)
+	case features.Multizone:
+		return d.setLegacyZones(colors, duration)
+	}
+
+	return fmt.Errorf("%w: multizone on %s", ErrUnsupported, d.GetMACAddress())
+}
+
+// setExtendedZones sets zones with a SetExtendedColorZones request per 82
+// zones, applying them together with the last request
+func (d *Device) setExtendedZones(colors []LIFXColor, duration time.Duration) error {
+	if len(colors) > 1<<16 {
+		return fmt.Errorf("too many zones: %d", len(colors))
+	}
+
+	for start := 0; start < len(colors); start += maxExtendedZones {
+		end := min(start+maxExtendedZones, len(colors))
+
+		apply := MultiZoneExtendedApplicationRequestNoApply
+		if end == len(colors) {
+			apply = MultiZoneExtendedApplicationRequestApply
+		}
+
+		if err := d.SetExtendedColorZones(uint16(start), colors[start:end], duration, apply); err != nil {
+			return fmt.Errorf("failed to set zones %d-%d: %w", start, end-1, err)
+		}
+	}
+
+	return nil
+}
+
+// setLegacyZones sets zones with a SetColorZones request per run of equal
+// colors, applying them together with the last request
+func (d *Device) setLegacyZones(colors []LIFXColor, duration time.Duration) error {
+	if len(colors) > maxLegacyZones {
+		return fmt.Errorf("too many zones: %d, at most %d are supported", len(colors), maxLegacyZones)
+	}
+
+	for start := 0; start < len(colors); {
+		end := start
+		for end+1 < len(colors) && colors[end+1] == colors[start] {
+			end++
+		}
+
+		apply := MultiZoneApplicationRequestNoApply
+		if end == len(colors)-1 {
+			apply = MultiZoneApplicationRequestApply
+		}
+
+		err := d.send(&SetColorZonesMessage{
+			StartIndex: uint8(start),
+			EndIndex:   uint8(end),
+			Color:      colors[start],
+			Duration:   uint32(duration.Milliseconds()),
+			Apply:      apply,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set zones %d-%d: %w", start, end, err)
+		}
+
+		start = end + 1
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package lifxlan
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// MultiZoneEffect is a firmware effect that runs on a multizone device
+// without further traffic from the client
+type MultiZoneEffect struct {
+	Type      MultiZoneEffectType          // MultiZoneEffectTypeOff or MultiZoneEffectTypeMove
+	Speed     time.Duration                // Time for the pattern to move across the strip once
+	Duration  time.Duration                // How long the effect runs, zero runs until it is stopped
+	Direction MultiZoneEffectMoveDirection // Direction of the MOVE effect
+}
+
+// SetMultiZoneEffect starts or stops a firmware effect on a multizone device
+func (d *Device) SetMultiZoneEffect(effect MultiZoneEffect) error {
+	if err := d.require("multizone", hasMultizone); err != nil {
+		return err
+	}
+	if effect.Type != MultiZoneEffectTypeOff && effect.Type != MultiZoneEffectTypeMove {
+		return fmt.Errorf("invalid multizone effect %s", effect.Type)
+	}
+
+	return d.send(&SetMultiZoneEffectMessage{
+		Settings: MultiZoneEffectSettings{
+			Instanceid: rand.Uint32(), // Identifies this run of the effect
+			Type:       effect.Type,
+			Speed:      uint32(effect.Speed.Milliseconds()),
+			Duration:   uint64(effect.Duration.Nanoseconds()),
+			Parameters: MultiZoneEffectParameter{Direction: effect.Direction},
+		},
+	})
+}
+
+// StartMoveEffect moves the current zone colors along the strip
+func (d *Device) StartMoveEffect(speed, duration time.Duration, direction MultiZoneEffectMoveDirection) error {
+	return d.SetMultiZoneEffect(MultiZoneEffect{
+		Type:      MultiZoneEffectTypeMove,
+		Speed:     speed,
+		Duration:  duration,
+		Direction: direction,
+	})
+}
+
+// StopMultiZoneEffect stops the running firmware effect
+func (d *Device) StopMultiZoneEffect() error {
+	return d.SetMultiZoneEffect(MultiZoneEffect{Type: MultiZoneEffectTypeOff})
+}
+
+// GetMultiZoneEffect returns the firmware effect running on a multizone device
+func (d *Device) GetMultiZoneEffect() (MultiZoneEffect, error) {
+	if err := d.require("multizone", hasMultizone); err != nil {
+		return MultiZoneEffect{}, err
+	}
+
+	var state StateMultiZoneEffectMessage
+	if err := d.request(&GetMultiZoneEffectMessage{}, &state); err != nil {
+		return MultiZoneEffect{}, err
+	}
+
+	return MultiZoneEffect{
+		Type:      state.Settings.Type,
+		Speed:     time.Duration(state.Settings.Speed) * time.Millisecond,
+		Duration:  time.Duration(state.Settings.Duration),
+		Direction: state.Settings.Parameters.Direction,
+	}, nil
+}
@@ -0,0 +1,77 @@
+package lifxlan
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMultiZoneEffect(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 31})
+	fake.reply(GetMultiZoneEffect, &StateMultiZoneEffectMessage{Settings: MultiZoneEffectSettings{
+		Type:       MultiZoneEffectTypeMove,
+		Speed:      3000,
+		Duration:   uint64(2 * time.Second),
+		Parameters: MultiZoneEffectParameter{Direction: MultiZoneEffectMoveDirectionTowards},
+	}})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	if err := device.StartMoveEffect(5*time.Second, time.Minute, MultiZoneEffectMoveDirectionAway); err != nil {
+		t.Fatal(err)
+	}
+
+	payloads := sentPayloads(t, fake, device, SetMultiZoneEffect)
+	if len(payloads) != 1 {
+		t.Fatalf("sent %d SetMultiZoneEffect packets, want 1", len(payloads))
+	}
+	var msg SetMultiZoneEffectMessage
+	if err := msg.UnmarshalBinary(payloads[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Speed is sent in milliseconds and duration in nanoseconds
+	settings := msg.Settings
+	settings.Instanceid = 0
+	want := MultiZoneEffectSettings{
+		Type:       MultiZoneEffectTypeMove,
+		Speed:      5000,
+		Duration:   60_000_000_000,
+		Parameters: MultiZoneEffectParameter{Direction: MultiZoneEffectMoveDirectionAway},
+	}
+	if settings != want {
+		t.Errorf("sent settings %+v, want %+v", settings, want)
+	}
+
+	effect, err := device.GetMultiZoneEffect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantEffect := MultiZoneEffect{
+		Type:      MultiZoneEffectTypeMove,
+		Speed:     3 * time.Second,
+		Duration:  2 * time.Second,
+		Direction: MultiZoneEffectMoveDirectionTowards,
+	}
+	if effect != wantEffect {
+		t.Errorf("got effect %+v, want %+v", effect, wantEffect)
+	}
+}
+
+func TestMultiZoneEffectUnsupported(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	if err := device.StartMoveEffect(time.Second, 0, MultiZoneEffectMoveDirectionAway); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("StartMoveEffect returned %v, want ErrUnsupported", err)
+	}
+	if _, err := device.GetMultiZoneEffect(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetMultiZoneEffect returned %v, want ErrUnsupported", err)
+	}
+	if n := len(sentPayloads(t, fake, device, SetMultiZoneEffect)); n != 0 {
+		t.Errorf("sent %d SetMultiZoneEffect packets to a bulb", n)
+	}
+}
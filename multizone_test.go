@@ -0,0 +1,267 @@
+package lifxlan
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestSetZonesFollowsFirmwareUpgrades checks that the LIFX Z only uses
+// extended multizone messages on firmware that adds them
+func TestSetZonesFollowsFirmwareUpgrades(t *testing.T) {
+	tests := []struct {
+		major, minor uint16
+		extended     bool
+		kelvin       [2]int
+	}{
+		{2, 76, false, [2]int{2500, 9000}},
+		{2, 77, true, [2]int{2500, 9000}},
+		{2, 80, true, [2]int{1500, 9000}},
+		{3, 0, true, [2]int{1500, 9000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(FirmwareVersion{Major: tt.major, Minor: tt.minor}.String(), func(t *testing.T) {
+			fake := newFakeDevice(t, 1)
+			fake.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 32})
+			fake.reply(GetHostFirmware, &StateHostFirmwareMessage{VersionMajor: tt.major, VersionMinor: tt.minor})
+
+			c := newTestClient(t)
+			device := fake.device(c)
+
+			features, err := device.Features()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if features.ExtendedMultizone != tt.extended {
+				t.Errorf("extended multizone %v, want %v", features.ExtendedMultizone, tt.extended)
+			}
+			if features.TemperatureRange != tt.kelvin {
+				t.Errorf("temperature range %v, want %v", features.TemperatureRange, tt.kelvin)
+			}
+
+			colors := make([]LIFXColor, 16)
+			if err := device.SetZones(colors, 0); err != nil {
+				t.Fatal(err)
+			}
+
+			extended := sentPayloads(t, fake, device, SetExtendedColorZones)
+			legacy := sentPayloads(t, fake, device, SetColorZones)
+			if got := len(extended) > 0; got != tt.extended || got == (len(legacy) > 0) {
+				t.Errorf("sent %d extended and %d legacy packets, want extended %v", len(extended), len(legacy), tt.extended)
+			}
+		})
+	}
+}
+
+func TestSetExtendedZonesSplitsPackets(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 117})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	colors := make([]LIFXColor, 200)
+	for i := range colors {
+		colors[i] = NewColor(uint16(i), 65535, 65535, 3500)
+	}
+
+	if err := device.setExtendedZones(colors, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		index, count int
+		apply        MultiZoneExtendedApplicationRequest
+	}{
+		{0, 82, MultiZoneExtendedApplicationRequestNoApply},
+		{82, 82, MultiZoneExtendedApplicationRequestNoApply},
+		{164, 36, MultiZoneExtendedApplicationRequestApply},
+	}
+
+	payloads := sentPayloads(t, fake, device, SetExtendedColorZones)
+	if len(payloads) != len(want) {
+		t.Fatalf("sent %d packets, want %d", len(payloads), len(want))
+	}
+
+	for i, payload := range payloads {
+		var msg SetExtendedColorZonesMessage
+		if err := msg.UnmarshalBinary(payload); err != nil {
+			t.Fatal(err)
+		}
+
+		w := want[i]
+		if int(msg.Index) != w.index || int(msg.ColorsCount) != w.count || msg.Apply != w.apply {
+			t.Errorf("packet %d has index %d, %d colors and %v, want %d, %d and %v",
+				i, msg.Index, msg.ColorsCount, msg.Apply, w.index, w.count, w.apply)
+		}
+		if first := colors[w.index]; msg.Colors[0] != first {
+			t.Errorf("packet %d starts with %v, want %v", i, msg.Colors[0], first)
+		}
+	}
+}
+
+// zoneColors returns n colors whose hue is their zone index, starting at index
+func zoneColors(index, n int) []LIFXColor {
+	colors := make([]LIFXColor, n)
+	for i := range colors {
+		colors[i] = NewColor(uint16(index+i), 65535, 65535, 3500)
+	}
+	return colors
+}
+
+func TestZoneCollector(t *testing.T) {
+	type packet struct {
+		index, n int
+		done     bool
+	}
+
+	tests := []struct {
+		name       string
+		start, end int
+		count      int
+		packets    []packet
+		want       []LIFXColor
+	}{
+		{
+			name: "several packets", start: 0, end: 23, count: 24,
+			packets: []packet{{0, 8, false}, {8, 8, false}, {16, 8, true}},
+			want:    zoneColors(0, 24),
+		},
+		{
+			name: "fewer zones than requested", start: 0, end: 255, count: 20,
+			packets: []packet{{0, 8, false}, {8, 8, false}, {16, 8, true}},
+			want:    zoneColors(0, 20),
+		},
+		{
+			name: "range inside packets", start: 5, end: 12, count: 20,
+			packets: []packet{{0, 8, false}, {8, 8, true}},
+			want:    zoneColors(5, 8),
+		},
+		{
+			name: "duplicate packets", start: 0, end: 15, count: 16,
+			packets: []packet{{0, 8, false}, {0, 8, false}, {8, 8, true}},
+			want:    zoneColors(0, 16),
+		},
+		{
+			name: "single zone", start: 3, end: 3, count: 20,
+			packets: []packet{{3, 1, true}},
+			want:    zoneColors(3, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zones := zoneCollector{start: tt.start, end: tt.end}
+			for i, p := range tt.packets {
+				if done := zones.add(tt.count, p.index, zoneColors(p.index, p.n)); done != p.done {
+					t.Fatalf("packet %d at zone %d: done %v, want %v", i, p.index, done, p.done)
+				}
+			}
+
+			if len(zones.zones) != len(tt.want) {
+				t.Fatalf("got %d zones, want %d", len(zones.zones), len(tt.want))
+			}
+			for i := range tt.want {
+				if zones.zones[i] != tt.want[i] {
+					t.Errorf("zone %d is %v, want %v", i, zones.zones[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestGetZonesLegacy reads every zone of a legacy strip, which are all sent
+// back at once in more packets than most requests receive
+func TestGetZonesLegacy(t *testing.T) {
+	const count = 255
+
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 31})
+	fake.handle(GetColorZones, func(payload []byte) []Message {
+		var req GetColorZonesMessage
+		if err := req.UnmarshalBinary(payload); err != nil {
+			return nil
+		}
+
+		var msgs []Message
+		for index := int(req.StartIndex) / 8 * 8; index <= min(int(req.EndIndex), count-1); index += 8 {
+			state := &StateMultiZoneMessage{Count: count, Index: uint8(index)}
+			copy(state.Colors[:], zoneColors(index, 8))
+			msgs = append(msgs, state)
+		}
+		return msgs
+	})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	zones, err := device.GetZones()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := zoneColors(0, count)
+	if len(zones) != len(want) {
+		t.Fatalf("got %d zones, want %d", len(zones), len(want))
+	}
+	for i := range want {
+		if zones[i] != want[i] {
+			t.Errorf("zone %d is %v, want %v", i, zones[i], want[i])
+		}
+	}
+
+	payloads := sentPayloads(t, fake, device, GetColorZones)
+	if len(payloads) != 1 || payloads[0][0] != 0 || payloads[0][1] != 255 {
+		t.Errorf("sent GetColorZones payloads %x, want a single request for zones 0-255", payloads)
+	}
+}
+
+func TestSetLegacyZonesRuns(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 31})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	red := NewColor(0, 65535, 65535, 3500)
+	green := NewColor(21845, 65535, 65535, 3500)
+	blue := NewColor(43690, 65535, 65535, 3500)
+	colors := []LIFXColor{red, red, red, green, blue, blue}
+
+	if err := device.SetZones(colors, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []SetColorZonesMessage{
+		{StartIndex: 0, EndIndex: 2, Color: red, Apply: MultiZoneApplicationRequestNoApply},
+		{StartIndex: 3, EndIndex: 3, Color: green, Apply: MultiZoneApplicationRequestNoApply},
+		{StartIndex: 4, EndIndex: 5, Color: blue, Apply: MultiZoneApplicationRequestApply},
+	}
+
+	payloads := sentPayloads(t, fake, device, SetColorZones)
+	if len(payloads) != len(want) {
+		t.Fatalf("sent %d packets, want %d", len(payloads), len(want))
+	}
+	for i, payload := range payloads {
+		var msg SetColorZonesMessage
+		if err := msg.UnmarshalBinary(payload); err != nil {
+			t.Fatal(err)
+		}
+		if msg != want[i] {
+			t.Errorf("packet %d is %+v, want %+v", i, msg, want[i])
+		}
+	}
+}
+
+func TestGetColorZonesUnsupported(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	if _, err := device.GetColorZones(0, 7); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetColorZones on a bulb returned %v, want ErrUnsupported", err)
+	}
+	if len(sentPayloads(t, fake, device, GetColorZones)) != 0 {
+		t.Error("GetColorZones was sent to a device without zones")
+	}
+}
@@ -5,35 +5,56 @@ import (
 	"time"
 )
 
+// The Build functions return a newly allocated packet. Each has an Append
+// counterpart that writes the packet to the end of a caller-supplied buffer
+// instead, allocating only if the buffer lacks capacity.
+
+// zeroTarget addresses all devices
+var zeroTarget [6]byte
+
 // BuildDiscoveryPacket creates a discovery packet
 func BuildDiscoveryPacket(source uint32) []byte {
-	header := DefaultHeader(source, make([]byte, 6), GetService, 0)
-	header.SetTagged(true)
+	return AppendDiscoveryPacket(make([]byte, 0, HeaderSize), source)
+}
 
-	return header[:]
+// AppendDiscoveryPacket appends a discovery packet to dst
+func AppendDiscoveryPacket(dst []byte, source uint32) []byte {
+	start := len(dst)
+	dst = AppendHeader(dst, source, zeroTarget[:], GetService, 0)
+	(*Header)(dst[start:]).SetTagged(true)
+
+	return dst
 }
 
 // BuildSetPowerPacket creates a packet to set the power state of a device
 func BuildSetPowerPacket(source uint32, target []byte, on bool) []byte {
-	payload := make([]byte, 2)
+	return AppendSetPowerPacket(make([]byte, 0, HeaderSize+2), source, target, on)
+}
+
+// AppendSetPowerPacket appends a packet to set the power state of a device to dst
+func AppendSetPowerPacket(dst []byte, source uint32, target []byte, on bool) []byte {
+	dst = AppendHeader(dst, source, target, SetPower, 2)
+	dst, payload := grow(dst, 2)
+
 	if on {
 		binary.LittleEndian.PutUint16(payload, 65535) // Power on
 	} else {
 		binary.LittleEndian.PutUint16(payload, 0) // Power off
 	}
 
-	header := DefaultHeader(source, target, SetPower, uint16(len(payload)))
-
-	// add the payload to the header to create the packet
-	packet := make([]byte, HeaderSize+len(payload))
-	copy(packet, header[:])
-	copy(packet[HeaderSize:], payload)
-	return packet
+	return dst
 }
 
 // BuildSetColorPacket creates a packet to set the color of a device
 func BuildSetColorPacket(source uint32, target []byte, color LIFXColor, duration time.Duration) []byte {
-	payload := make([]byte, 13)
+	return AppendSetColorPacket(make([]byte, 0, HeaderSize+13), source, target, color, duration)
+}
+
+// AppendSetColorPacket appends a packet to set the color of a device to dst
+func AppendSetColorPacket(dst []byte, source uint32, target []byte, color LIFXColor, duration time.Duration) []byte {
+	dst = AppendHeader(dst, source, target, SetColor, 13)
+	dst, payload := grow(dst, 13)
+
 	binary.LittleEndian.PutUint16(payload[1:3], color.hue)
 	binary.LittleEndian.PutUint16(payload[3:5], color.saturation)
 	binary.LittleEndian.PutUint16(payload[5:7], color.brightness)
@@ -43,51 +64,50 @@ func BuildSetColorPacket(source uint32, target []byte, color LIFXColor, duration
 	durationMs := uint32(duration.Milliseconds())
 	binary.LittleEndian.PutUint32(payload[9:13], durationMs)
 
-	header := DefaultHeader(source, target, SetColor, uint16(len(payload)))
-
-	// add the payload to the header to create the packet
-	packet := make([]byte, HeaderSize+len(payload))
-	copy(packet, header[:])
-	copy(packet[HeaderSize:], payload)
-	return packet
+	return dst
 }
 
 // BuildGetLabelPacket creates a packet to get the label of a device
 func BuildGetLabelPacket(source uint32, target []byte) []byte {
-	header := DefaultHeader(source, target, GetLabel, 0)
+	return AppendGetLabelPacket(make([]byte, 0, HeaderSize), source, target)
+}
 
-	return header[:]
+// AppendGetLabelPacket appends a packet to get the label of a device to dst
+func AppendGetLabelPacket(dst []byte, source uint32, target []byte) []byte {
+	return AppendHeader(dst, source, target, GetLabel, 0)
 }
 
 // BuildSetLabelPacket creates a packet to set the label of a device
 func BuildSetLabelPacket(source uint32, target []byte, label string) []byte {
-	payload := make([]byte, 32)
-	copy(payload, label)
+	return AppendSetLabelPacket(make([]byte, 0, HeaderSize+32), source, target, label)
+}
 
-	header := DefaultHeader(source, target, SetLabel, uint16(len(payload)))
+// AppendSetLabelPacket appends a packet to set the label of a device to dst
+func AppendSetLabelPacket(dst []byte, source uint32, target []byte, label string) []byte {
+	dst = AppendHeader(dst, source, target, SetLabel, 32)
+	dst, payload := grow(dst, 32)
+	copy(payload, label)
 
-	// add the payload to the header to create the packet
-	packet := make([]byte, HeaderSize+len(payload))
-	copy(packet, header[:])
-	copy(packet[HeaderSize:], payload)
-	return packet
+	return dst
 }
 
 // BuildGetVersionPacket creates a packet to get the version of a device
 func BuildGetVersionPacket(source uint32, target []byte) []byte {
-	header := DefaultHeader(source, target, GetVersion, 0)
+	return AppendGetVersionPacket(make([]byte, 0, HeaderSize), source, target)
+}
 
-	return header[:]
+// AppendGetVersionPacket appends a packet to get the version of a device to dst
+func AppendGetVersionPacket(dst []byte, source uint32, target []byte) []byte {
+	return AppendHeader(dst, source, target, GetVersion, 0)
 }
 
 func BuildEchoRequestPacket(source uint32, target []byte, echo []byte) []byte {
-	payload := echo
+	return AppendEchoRequestPacket(make([]byte, 0, HeaderSize+len(echo)), source, target, echo)
+}
 
-	header := DefaultHeader(source, target, EchoRequest, uint16(len(payload)))
+// AppendEchoRequestPacket appends an echo request carrying the given payload to dst
+func AppendEchoRequestPacket(dst []byte, source uint32, target []byte, echo []byte) []byte {
+	dst = AppendHeader(dst, source, target, EchoRequest, uint16(len(echo)))
 
-	// add the payload to the header to create the packet
-	packet := make([]byte, HeaderSize+len(payload))
-	copy(packet, header[:])
-	copy(packet[HeaderSize:], payload)
-	return packet
+	return append(dst, echo...)
 }
@@ -0,0 +1,170 @@
+package lifxlan
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+var benchTarget = []byte{0xd0, 0x73, 0xd5, 0x01, 0x02, 0x03}
+
+// benchSink keeps encoded packets alive so the compiler can't elide them
+var benchSink []byte
+
+// stripsPerFrame matches a controller driving 30 multizone strips
+const stripsPerFrame = 30
+
+// unhex decodes a hex dump, ignoring whitespace
+func unhex(t testing.TB, dump string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(strings.Join(strings.Fields(dump), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestBuildPacketsGolden compares each builder against a known good datagram.
+// SetColor and discovery are the examples from the protocol documentation,
+// the others follow the same layout with a source of 0x12345678.
+func TestBuildPacketsGolden(t *testing.T) {
+	const source = 0x12345678
+
+	tests := []struct {
+		name   string
+		packet []byte
+		want   string
+	}{
+		{
+			name:   "Discovery",
+			packet: BuildDiscoveryPacket(0),
+			want: `24 00 00 34 00 00 00 00  00 00 00 00 00 00 00 00
+			       00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00
+			       02 00 00 00`,
+		},
+		{
+			// The documentation example is tagged, the builder only addresses a single device
+			name:   "SetColor",
+			packet: BuildSetColorPacket(0, zeroTarget[:], NewColor(21845, 65535, 65535, 3500), 1024*time.Millisecond),
+			want: `31 00 00 14 00 00 00 00  00 00 00 00 00 00 00 00
+			       00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00
+			       66 00 00 00
+			       00 55 55 ff ff ff ff ac  0d 00 04 00 00`,
+		},
+		{
+			name:   "SetPowerOn",
+			packet: BuildSetPowerPacket(source, benchTarget, true),
+			want: `26 00 00 14 78 56 34 12  d0 73 d5 01 02 03 00 00
+			       00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00
+			       15 00 00 00
+			       ff ff`,
+		},
+		{
+			name:   "SetPowerOff",
+			packet: BuildSetPowerPacket(source, benchTarget, false),
+			want: `26 00 00 14 78 56 34 12  d0 73 d5 01 02 03 00 00
+			       00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00
+			       15 00 00 00
+			       00 00`,
+		},
+		{
+			name:   "GetLabel",
+			packet: BuildGetLabelPacket(source, benchTarget),
+			want: `24 00 00 14 78 56 34 12  d0 73 d5 01 02 03 00 00
+			       00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00
+			       17 00 00 00`,
+		},
+		{
+			name:   "SetLabel",
+			packet: BuildSetLabelPacket(source, benchTarget, "Kitchen"),
+			want: `44 00 00 14 78 56 34 12  d0 73 d5 01 02 03 00 00
+			       00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00
+			       18 00 00 00
+			       4b 69 74 63 68 65 6e 00  00 00 00 00 00 00 00 00
+			       00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00`,
+		},
+		{
+			name:   "GetVersion",
+			packet: BuildGetVersionPacket(source, benchTarget),
+			want: `24 00 00 14 78 56 34 12  d0 73 d5 01 02 03 00 00
+			       00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00
+			       20 00 00 00`,
+		},
+		{
+			name:   "EchoRequest",
+			packet: BuildEchoRequestPacket(source, benchTarget, []byte{0xde, 0xad, 0xbe, 0xef}),
+			want: `28 00 00 14 78 56 34 12  d0 73 d5 01 02 03 00 00
+			       00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00
+			       3a 00 00 00
+			       de ad be ef`,
+		},
+	}
+
+	for _, tt := range tests {
+		if want := unhex(t, tt.want); !bytes.Equal(tt.packet, want) {
+			t.Errorf("%s:\ngot  % x\nwant % x", tt.name, tt.packet, want)
+		}
+	}
+}
+
+// TestAppendPacketsDoNotAllocate ensures the append encoders don't allocate
+// when the destination buffer has enough capacity
+func TestAppendPacketsDoNotAllocate(t *testing.T) {
+	buf := make([]byte, 0, bufferSize)
+	color := NewColor(21845, 65535, 65535, 3500)
+	echo := make([]byte, 64)
+
+	encoders := map[string]func(){
+		"Discovery":   func() { AppendDiscoveryPacket(buf[:0], 1) },
+		"SetPower":    func() { AppendSetPowerPacket(buf[:0], 1, benchTarget, true) },
+		"SetColor":    func() { AppendSetColorPacket(buf[:0], 1, benchTarget, color, time.Second) },
+		"GetLabel":    func() { AppendGetLabelPacket(buf[:0], 1, benchTarget) },
+		"SetLabel":    func() { AppendSetLabelPacket(buf[:0], 1, benchTarget, "Nightstand") },
+		"GetVersion":  func() { AppendGetVersionPacket(buf[:0], 1, benchTarget) },
+		"EchoRequest": func() { AppendEchoRequestPacket(buf[:0], 1, benchTarget, echo) },
+	}
+
+	for name, encode := range encoders {
+		if allocs := testing.AllocsPerRun(100, encode); allocs != 0 {
+			t.Errorf("%s: got %v allocations per packet, want 0", name, allocs)
+		}
+	}
+}
+
+func BenchmarkBuildSetColorPacket(b *testing.B) {
+	color := NewColor(21845, 65535, 65535, 3500)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		benchSink = BuildSetColorPacket(1, benchTarget, color, 50*time.Millisecond)
+	}
+}
+
+func BenchmarkAppendSetColorPacket(b *testing.B) {
+	color := NewColor(21845, 65535, 65535, 3500)
+	buf := make([]byte, 0, bufferSize)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf = AppendSetColorPacket(buf[:0], 1, benchTarget, color, 50*time.Millisecond)
+	}
+	benchSink = buf
+}
+
+// BenchmarkFramePooled encodes one animation frame for every strip using
+// pooled buffers, as the Device methods do
+func BenchmarkFramePooled(b *testing.B) {
+	color := NewColor(21845, 65535, 65535, 3500)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		for s := 0; s < stripsPerFrame; s++ {
+			buf := getBuffer()
+			benchSink = AppendSetColorPacket((*buf)[:0], 1, benchTarget, color, 50*time.Millisecond)
+			putBuffer(buf)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// Code generated by protogen from protocol.yml, protocol_extra.yml. DO NOT EDIT.
+
+package lifxlan
+
+import "fmt"
+
+// Device messages
+
+const (
+	GetService        PacketType = 2   // DeviceGetService
+	StateService      PacketType = 3   // DeviceStateService
+	GetHostFirmware   PacketType = 14  // DeviceGetHostFirmware
+	StateHostFirmware PacketType = 15  // DeviceStateHostFirmware
+	GetWifiInfo       PacketType = 16  // DeviceGetWifiInfo
+	StateWifiInfo     PacketType = 17  // DeviceStateWifiInfo
+	GetWifiFirmware   PacketType = 18  // DeviceGetWifiFirmware
+	StateWifiFirmware PacketType = 19  // DeviceStateWifiFirmware
+	GetPower          PacketType = 20  // DeviceGetPower
+	SetPower          PacketType = 21  // DeviceSetPower
+	StatePower        PacketType = 22  // DeviceStatePower
+	GetLabel          PacketType = 23  // DeviceGetLabel
+	SetLabel          PacketType = 24  // DeviceSetLabel
+	StateLabel        PacketType = 25  // DeviceStateLabel
+	GetVersion        PacketType = 32  // DeviceGetVersion
+	StateVersion      PacketType = 33  // DeviceStateVersion
+	GetInfo           PacketType = 34  // DeviceGetInfo
+	StateInfo         PacketType = 35  // DeviceStateInfo
+	SetReboot         PacketType = 38  // DeviceSetReboot
+	Acknowledgement   PacketType = 45  // DeviceAcknowledgement
+	GetLocation       PacketType = 48  // DeviceGetLocation
+	SetLocation       PacketType = 49  // DeviceSetLocation
+	StateLocation     PacketType = 50  // DeviceStateLocation
+	GetGroup          PacketType = 51  // DeviceGetGroup
+	SetGroup          PacketType = 52  // DeviceSetGroup
+	StateGroup        PacketType = 53  // DeviceStateGroup
+	EchoRequest       PacketType = 58  // DeviceEchoRequest
+	EchoResponse      PacketType = 59  // DeviceEchoResponse
+	StateUnhandled    PacketType = 223 // DeviceStateUnhandled
+)
+
+// Light messages
+
+const (
+	GetColor                   PacketType = 101 // LightGet
+	SetColor                   PacketType = 102 // LightSetColor
+	SetWaveform                PacketType = 103 // LightSetWaveform
+	LightState                 PacketType = 107 // LightState
+	GetLightPower              PacketType = 116 // LightGetPower
+	SetLightPower              PacketType = 117 // LightSetPower
+	StateLightPower            PacketType = 118 // LightStatePower
+	SetWaveformOptional        PacketType = 119 // LightSetWaveformOptional
+	GetInfrared                PacketType = 120 // LightGetInfrared
+	StateInfrared              PacketType = 121 // LightStateInfrared
+	SetInfrared                PacketType = 122 // LightSetInfrared
+	GetHevCycle                PacketType = 142 // LightGetHevCycle
+	SetHevCycle                PacketType = 143 // LightSetHevCycle
+	StateHevCycle              PacketType = 144 // LightStateHevCycle
+	GetHevCycleConfiguration   PacketType = 145 // LightGetHevCycleConfiguration
+	SetHevCycleConfiguration   PacketType = 146 // LightSetHevCycleConfiguration
+	StateHevCycleConfiguration PacketType = 147 // LightStateHevCycleConfiguration
+	GetLastHevCycleResult      PacketType = 148 // LightGetLastHevCycleResult
+	StateLastHevCycleResult    PacketType = 149 // LightStateLastHevCycleResult
+)
+
+// Sensor messages
+
+const (
+	SensorGetAmbientLight PacketType = 401 // SensorGetAmbientLight
+	StateAmbientLight     PacketType = 402 // SensorStateAmbientLight
+)
+
+// Multi zone messages
+
+const (
+	SetColorZones           PacketType = 501 // MultiZoneSetColorZones
+	GetColorZones           PacketType = 502 // MultiZoneGetColorZones
+	StateZone               PacketType = 503 // MultiZoneStateZone
+	StateMultiZone          PacketType = 506 // MultiZoneStateMultiZone
+	GetMultiZoneEffect      PacketType = 507 // MultiZoneGetEffect
+	SetMultiZoneEffect      PacketType = 508 // MultiZoneSetEffect
+	StateMultiZoneEffect    PacketType = 509 // MultiZoneStateEffect
+	SetExtendedColorZones   PacketType = 510 // MultiZoneExtendedSetColorZones
+	GetExtendedColorZones   PacketType = 511 // MultiZoneExtendedGetColorZones
+	StateExtendedColorZones PacketType = 512 // MultiZoneExtendedStateMultiZone
+)
+
+// Tile messages
+
+const (
+	GetDeviceChain   PacketType = 701 // TileGetDeviceChain
+	StateDeviceChain PacketType = 702 // TileStateDeviceChain
+	SetUserPosition  PacketType = 703 // TileSetUserPosition
+	Get64            PacketType = 707 // TileGet64
+	State64          PacketType = 711 // TileState64
+	Set64            PacketType = 715 // TileSet64
+	GetTileEffect    PacketType = 718 // TileGetEffect
+	SetTileEffect    PacketType = 719 // TileSetEffect
+	StateTileEffect  PacketType = 720 // TileStateEffect
+)
+
+// Relay messages
+
+const (
+	GetRPower   PacketType = 816 // RelayGetRPower
+	SetRPower   PacketType = 817 // RelaySetRPower
+	StateRPower PacketType = 818 // RelayStateRPower
+)
+
+// String returns the name of the packet type
+func (t PacketType) String() string {
+	switch t {
+	case GetService:
+		return "GetService"
+	case StateService:
+		return "StateService"
+	case GetHostFirmware:
+		return "GetHostFirmware"
+	case StateHostFirmware:
+		return "StateHostFirmware"
+	case GetWifiInfo:
+		return "GetWifiInfo"
+	case StateWifiInfo:
+		return "StateWifiInfo"
+	case GetWifiFirmware:
+		return "GetWifiFirmware"
+	case StateWifiFirmware:
+		return "StateWifiFirmware"
+	case GetPower:
+		return "GetPower"
+	case SetPower:
+		return "SetPower"
+	case StatePower:
+		return "StatePower"
+	case GetLabel:
+		return "GetLabel"
+	case SetLabel:
+		return "SetLabel"
+	case StateLabel:
+		return "StateLabel"
+	case GetVersion:
+		return "GetVersion"
+	case StateVersion:
+		return "StateVersion"
+	case GetInfo:
+		return "GetInfo"
+	case StateInfo:
+		return "StateInfo"
+	case SetReboot:
+		return "SetReboot"
+	case Acknowledgement:
+		return "Acknowledgement"
+	case GetLocation:
+		return "GetLocation"
+	case SetLocation:
+		return "SetLocation"
+	case StateLocation:
+		return "StateLocation"
+	case GetGroup:
+		return "GetGroup"
+	case SetGroup:
+		return "SetGroup"
+	case StateGroup:
+		return "StateGroup"
+	case EchoRequest:
+		return "EchoRequest"
+	case EchoResponse:
+		return "EchoResponse"
+	case GetColor:
+		return "GetColor"
+	case SetColor:
+		return "SetColor"
+	case SetWaveform:
+		return "SetWaveform"
+	case LightState:
+		return "LightState"
+	case GetLightPower:
+		return "GetLightPower"
+	case SetLightPower:
+		return "SetLightPower"
+	case StateLightPower:
+		return "StateLightPower"
+	case SetWaveformOptional:
+		return "SetWaveformOptional"
+	case GetInfrared:
+		return "GetInfrared"
+	case StateInfrared:
+		return "StateInfrared"
+	case SetInfrared:
+		return "SetInfrared"
+	case GetHevCycle:
+		return "GetHevCycle"
+	case SetHevCycle:
+		return "SetHevCycle"
+	case StateHevCycle:
+		return "StateHevCycle"
+	case GetHevCycleConfiguration:
+		return "GetHevCycleConfiguration"
+	case SetHevCycleConfiguration:
+		return "SetHevCycleConfiguration"
+	case StateHevCycleConfiguration:
+		return "StateHevCycleConfiguration"
+	case GetLastHevCycleResult:
+		return "GetLastHevCycleResult"
+	case StateLastHevCycleResult:
+		return "StateLastHevCycleResult"
+	case StateUnhandled:
+		return "StateUnhandled"
+	case SensorGetAmbientLight:
+		return "SensorGetAmbientLight"
+	case StateAmbientLight:
+		return "StateAmbientLight"
+	case SetColorZones:
+		return "SetColorZones"
+	case GetColorZones:
+		return "GetColorZones"
+	case StateZone:
+		return "StateZone"
+	case StateMultiZone:
+		return "StateMultiZone"
+	case GetMultiZoneEffect:
+		return "GetMultiZoneEffect"
+	case SetMultiZoneEffect:
+		return "SetMultiZoneEffect"
+	case StateMultiZoneEffect:
+		return "StateMultiZoneEffect"
+	case SetExtendedColorZones:
+		return "SetExtendedColorZones"
+	case GetExtendedColorZones:
+		return "GetExtendedColorZones"
+	case StateExtendedColorZones:
+		return "StateExtendedColorZones"
+	case GetDeviceChain:
+		return "GetDeviceChain"
+	case StateDeviceChain:
+		return "StateDeviceChain"
+	case SetUserPosition:
+		return "SetUserPosition"
+	case Get64:
+		return "Get64"
+	case State64:
+		return "State64"
+	case Set64:
+		return "Set64"
+	case GetTileEffect:
+		return "GetTileEffect"
+	case SetTileEffect:
+		return "SetTileEffect"
+	case StateTileEffect:
+		return "StateTileEffect"
+	case GetRPower:
+		return "GetRPower"
+	case SetRPower:
+		return "SetRPower"
+	case StateRPower:
+		return "StateRPower"
+	}
+	return fmt.Sprintf("PacketType(%d)", uint16(t))
+}
@@ -0,0 +1,82 @@
+package lifxlan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// defaultPollInterval is used when PollOptions doesn't set an interval
+const defaultPollInterval = 10 * time.Second
+
+// PollOptions selects what Client.Poll queries from each device
+type PollOptions struct {
+	Interval     time.Duration // Time between polls, defaults to 10 seconds
+	State        bool          // Query the color, power and label of lights
+	AmbientLight bool          // Query the ambient light sensor
+
+	// OnPoll is called after each device has been polled, with the first
+	// error encountered. The results are cached in the device information.
+	// Errors are discarded when it is nil.
+	OnPoll func(device *Device, err error)
+}
+
+// Poll queries every device at the configured interval, keeping the cached
+// device information current. It returns when ctx is done or the client is closed.
+func (c *Client) Poll(ctx context.Context, opts PollOptions) error {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, device := range c.GetDevices() {
+			// Stop between devices as well as between polls
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			err := device.poll(opts)
+			if errors.Is(err, ErrClientClosed) {
+				return ErrClientClosed
+			}
+
+			if opts.OnPoll != nil {
+				opts.OnPoll(device, err)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.done:
+			return ErrClientClosed
+		}
+	}
+}
+
+// poll runs the queries selected in opts and returns the first error
+func (d *Device) poll(opts PollOptions) error {
+	var firstErr error
+
+	// Switches have no light state to query
+	if opts.State && d.Info().Kind != KindRelay {
+		if _, err := d.GetState(); err != nil {
+			firstErr = fmt.Errorf("failed to get state: %w", err)
+		}
+	}
+
+	// Devices known to lack a sensor are skipped rather than reported on every poll
+	if opts.AmbientLight && d.hasSensor() {
+		if _, err := d.GetAmbientLight(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to get ambient light: %w", err)
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,37 @@
+package lifxlan
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPollReportsToOnPoll(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetColor, &StateUnhandledMessage{UnhandledType: uint16(GetColor)})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var polled *Device
+	var pollErr error
+	err := c.Poll(ctx, PollOptions{
+		Interval: time.Hour,
+		State:    true,
+		OnPoll: func(d *Device, err error) {
+			polled, pollErr = d, err
+			cancel()
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Poll returned %v, want context.Canceled", err)
+	}
+
+	if polled != device || !errors.Is(pollErr, ErrUnsupported) {
+		t.Errorf("OnPoll got %v and %v, want the device and ErrUnsupported", polled, pollErr)
+	}
+}
@@ -0,0 +1,59 @@
+package lifxlan
+
+import "time"
+
+// GetPower returns the power level of the device, 0 when off and 65535 when on
+func (d *Device) GetPower() (uint16, error) {
+	var state StatePowerMessage
+	if err := d.request(&GetPowerMessage{}, &state); err != nil {
+		return 0, err
+	}
+
+	// Update the device's cached power level
+	d.update(func(info *DeviceInfo) {
+		info.Power = state.Level
+	})
+
+	return state.Level, nil
+}
+
+// IsOn queries the device and returns true if it is powered on
+func (d *Device) IsOn() (bool, error) {
+	level, err := d.GetPower()
+	if err != nil {
+		return false, err
+	}
+
+	return level > 0, nil
+}
+
+// powerLevel converts an on/off state to a power level
+func powerLevel(on bool) uint16 {
+	if on {
+		return 65535
+	}
+	return 0
+}
+
+// SetPowerWithDuration turns a light on or off, fading over the given duration
+func (d *Device) SetPowerWithDuration(on bool, duration time.Duration) error {
+	return d.send(&SetLightPowerMessage{
+		Level:    powerLevel(on),
+		Duration: uint32(duration.Milliseconds()),
+	})
+}
+
+// GetLightPower returns the power level of a light, 0 when off and 65535 when on
+func (d *Device) GetLightPower() (uint16, error) {
+	var state StateLightPowerMessage
+	if err := d.request(&GetLightPowerMessage{}, &state); err != nil {
+		return 0, err
+	}
+
+	// Update the device's cached power level
+	d.update(func(info *DeviceInfo) {
+		info.Power = state.Level
+	})
+
+	return state.Level, nil
+}
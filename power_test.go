@@ -0,0 +1,94 @@
+package lifxlan
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetPower(t *testing.T) {
+	for _, level := range []uint16{0, 65535} {
+		fake := newFakeDevice(t, 1)
+		fake.reply(GetPower, &StatePowerMessage{Level: level})
+
+		c := newTestClient(t)
+		device := fake.device(c)
+
+		on, err := device.IsOn()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if on != (level > 0) || device.Info().Power != level {
+			t.Errorf("level %d: IsOn %v, cached %d", level, on, device.Info().Power)
+		}
+
+		if n := len(sentPayloads(t, fake, device, GetPower)); n != 1 {
+			t.Errorf("level %d: sent %d GetPower packets, want 1", level, n)
+		}
+	}
+}
+
+func TestGetPowerUnhandled(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetPower, &StateUnhandledMessage{UnhandledType: uint16(GetPower)})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	if _, err := device.GetPower(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetPower returned %v, want ErrUnsupported", err)
+	}
+}
+
+func TestSetPowerWithDuration(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	if err := device.SetPowerWithDuration(true, 1500*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := device.SetPowerWithDuration(false, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []SetLightPowerMessage{
+		{Level: 65535, Duration: 1500},
+		{Level: 0, Duration: 0},
+	}
+
+	payloads := sentPayloads(t, fake, device, SetLightPower)
+	if len(payloads) != len(want) {
+		t.Fatalf("sent %d SetLightPower packets, want %d", len(payloads), len(want))
+	}
+	for i, payload := range payloads {
+		var msg SetLightPowerMessage
+		if err := msg.UnmarshalBinary(payload); err != nil {
+			t.Fatal(err)
+		}
+		if msg != want[i] {
+			t.Errorf("packet %d is %+v, want %+v", i, msg, want[i])
+		}
+	}
+}
+
+func TestGetLightPower(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetLightPower, &StateLightPowerMessage{Level: 65535})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	level, err := device.GetLightPower()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level != 65535 || device.Info().Power != 65535 {
+		t.Errorf("got level %d, cached %d, want 65535", level, device.Info().Power)
+	}
+
+	fake.reply(GetLightPower, &StateUnhandledMessage{UnhandledType: uint16(GetLightPower)})
+	if _, err := device.GetLightPower(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetLightPower on a device without lights returned %v, want ErrUnsupported", err)
+	}
+}
@@ -40,6 +40,59 @@ type Product struct {
 	}
 }
 
+// FeaturesAt returns the features of the product when running the given host
+// firmware, applying every upgrade included in that firmware in order
+func (p Product) FeaturesAt(firmware FirmwareVersion) Features {
+	features := p.Features
+	for _, upgrade := range p.Upgrades {
+		if firmware.AtLeast(uint16(upgrade.Major), uint16(upgrade.Minor)) {
+			features = features.merge(upgrade.Features)
+		}
+	}
+
+	return features
+}
+
+// merge adds the features listed in an upgrade. Upgrades only list what they
+// add or change, so flags are combined and a temperature range replaces the old one.
+func (f Features) merge(upgrade Features) Features {
+	f.Hev = f.Hev || upgrade.Hev
+	f.Color = f.Color || upgrade.Color
+	f.Chain = f.Chain || upgrade.Chain
+	f.Matrix = f.Matrix || upgrade.Matrix
+	f.Relays = f.Relays || upgrade.Relays
+	f.Buttons = f.Buttons || upgrade.Buttons
+	f.Infrared = f.Infrared || upgrade.Infrared
+	f.Multizone = f.Multizone || upgrade.Multizone
+	f.ExtendedMultizone = f.ExtendedMultizone || upgrade.ExtendedMultizone
+
+	if upgrade.TemperatureRange != [2]int{} {
+		f.TemperatureRange = upgrade.TemperatureRange
+	}
+
+	return f
+}
+
+// DeviceKind is the broad category of a product
+type DeviceKind string
+
+const (
+	KindUnknown DeviceKind = ""      // Product not known yet
+	KindLight   DeviceKind = "light" // Bulbs, strips and matrix devices
+	KindRelay   DeviceKind = "relay" // Switches that control relays rather than a light
+)
+
+// Kind returns the category of the product
+func (p Product) Kind() DeviceKind {
+	switch {
+	case p.ProductID == 0:
+		return KindUnknown
+	case p.Features.Relays:
+		return KindRelay
+	}
+	return KindLight
+}
+
 var (
 	//go:embed products.json
 	productsJSON []byte
@@ -0,0 +1,74 @@
+package lifxlan
+
+import "sync"
+
+// registry is a concurrency-safe collection of devices keyed by MAC address.
+// Each device is stored once, so pointers handed out stay valid and observe updates.
+type registry struct {
+	mu      sync.RWMutex
+	byMAC   map[string]*Device // Keyed by the raw MAC bytes
+	ordered []*Device          // Insertion order for stable iteration
+}
+
+// get returns the device with the given MAC address, or nil if unknown
+func (r *registry) get(mac []byte) *Device {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.byMAC[string(mac)]
+}
+
+// add stores a device unless one with the same MAC address already exists.
+// It returns the stored device and whether it was newly added.
+func (r *registry) add(device *Device) (*Device, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.byMAC[string(device.mac)]; ok {
+		return existing, false
+	}
+
+	if r.byMAC == nil {
+		r.byMAC = make(map[string]*Device)
+	}
+	r.byMAC[string(device.mac)] = device
+	r.ordered = append(r.ordered, device)
+
+	return device, true
+}
+
+// replace swaps the contents of the registry for the given devices
+func (r *registry) replace(devices []*Device) {
+	byMAC := make(map[string]*Device, len(devices))
+	ordered := make([]*Device, 0, len(devices))
+	for _, device := range devices {
+		if _, ok := byMAC[string(device.mac)]; ok {
+			continue // Keep the first entry for duplicate MAC addresses
+		}
+		byMAC[string(device.mac)] = device
+		ordered = append(ordered, device)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byMAC = byMAC
+	r.ordered = ordered
+}
+
+// list returns a copy of the devices in insertion order
+func (r *registry) list() []*Device {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]*Device(nil), r.ordered...)
+}
+
+// clear removes all devices
+func (r *registry) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byMAC = nil
+	r.ordered = nil
+}
@@ -0,0 +1,82 @@
+package lifxlan
+
+import "fmt"
+
+// relayCount is the number of relays on a LIFX Switch
+const relayCount = 4
+
+// hasRelays reports whether a product has relays
+func hasRelays(f Features) bool { return f.Relays }
+
+// checkRelayIndex returns an error for relay indexes the Switch doesn't have
+func checkRelayIndex(index int) error {
+	if index < 0 || index >= relayCount {
+		return fmt.Errorf("invalid relay index %d, must be 0 to %d", index, relayCount-1)
+	}
+	return nil
+}
+
+// GetRelayPower returns true if the relay at index is on
+func (d *Device) GetRelayPower(index int) (bool, error) {
+	if err := checkRelayIndex(index); err != nil {
+		return false, err
+	}
+	if err := d.require("relays", hasRelays); err != nil {
+		return false, err
+	}
+
+	var state StateRPowerMessage
+	err := d.collect(&GetRPowerMessage{RelayIndex: uint8(index)}, waiterQueue, func(h *Header, payload []byte) (bool, error) {
+		if h.Type() != StateRPower {
+			return false, nil
+		}
+		if err := state.UnmarshalBinary(payload); err != nil {
+			return false, err
+		}
+
+		// Skip responses for other relays
+		return int(state.RelayIndex) == index, nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return state.Level > 0, nil
+}
+
+// SetRelayPower turns the relay at index on or off
+func (d *Device) SetRelayPower(index int, on bool) error {
+	if err := checkRelayIndex(index); err != nil {
+		return err
+	}
+	if err := d.require("relays", hasRelays); err != nil {
+		return err
+	}
+
+	return d.send(&SetRPowerMessage{RelayIndex: uint8(index), Level: powerLevel(on)})
+}
+
+// GetRelays returns the power state of every relay, indexed by relay
+func (d *Device) GetRelays() ([]bool, error) {
+	relays := make([]bool, relayCount)
+	for i := range relays {
+		on, err := d.GetRelayPower(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get relay %d: %w", i, err)
+		}
+		relays[i] = on
+	}
+
+	return relays, nil
+}
+
+// setAllRelays turns every relay on or off
+func (d *Device) setAllRelays(on bool) error {
+	for i := 0; i < relayCount; i++ {
+		if err := d.SetRelayPower(i, on); err != nil {
+			return fmt.Errorf("failed to set relay %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
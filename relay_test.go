@@ -0,0 +1,103 @@
+package lifxlan
+
+import (
+	"errors"
+	"testing"
+)
+
+// newFakeSwitch returns a fake LIFX Switch whose even relays are on
+func newFakeSwitch(t *testing.T) (*fakeDevice, *Device) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 70})
+	fake.handle(GetRPower, func(payload []byte) []Message {
+		var req GetRPowerMessage
+		if err := req.UnmarshalBinary(payload); err != nil {
+			return nil
+		}
+
+		// Answer for another relay first, which must be skipped
+		other := &StateRPowerMessage{RelayIndex: (req.RelayIndex + 1) % relayCount, Level: 65535}
+		state := &StateRPowerMessage{RelayIndex: req.RelayIndex, Level: powerLevel(req.RelayIndex%2 == 0)}
+		return []Message{other, state}
+	})
+
+	c := newTestClient(t)
+	return fake, fake.device(c)
+}
+
+func TestGetRelays(t *testing.T) {
+	fake, device := newFakeSwitch(t)
+
+	relays, err := device.GetRelays()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []bool{true, false, true, false}
+	if len(relays) != len(want) {
+		t.Fatalf("got %d relays, want %d", len(relays), len(want))
+	}
+	for i := range want {
+		if relays[i] != want[i] {
+			t.Errorf("relay %d on %v, want %v", i, relays[i], want[i])
+		}
+	}
+
+	// Each relay is queried in turn
+	payloads := sentPayloads(t, fake, device, GetRPower)
+	if len(payloads) != relayCount {
+		t.Fatalf("sent %d GetRPower packets, want %d", len(payloads), relayCount)
+	}
+	for i, payload := range payloads {
+		if int(payload[0]) != i {
+			t.Errorf("packet %d asks for relay %d", i, payload[0])
+		}
+	}
+}
+
+func TestSwitchTurnOnSetsRelays(t *testing.T) {
+	fake, device := newFakeSwitch(t)
+
+	// The product has to be known for the device to be treated as a switch
+	if _, err := device.GetProduct(); err != nil {
+		t.Fatal(err)
+	}
+	if err := device.TurnOn(); err != nil {
+		t.Fatal(err)
+	}
+
+	payloads := sentPayloads(t, fake, device, SetRPower)
+	if len(payloads) != relayCount {
+		t.Fatalf("sent %d SetRPower packets, want %d", len(payloads), relayCount)
+	}
+	for i, payload := range payloads {
+		var msg SetRPowerMessage
+		if err := msg.UnmarshalBinary(payload); err != nil {
+			t.Fatal(err)
+		}
+		if want := (SetRPowerMessage{RelayIndex: uint8(i), Level: 65535}); msg != want {
+			t.Errorf("packet %d is %+v, want %+v", i, msg, want)
+		}
+	}
+	if n := len(sentPayloads(t, fake, device, SetPower)); n != 0 {
+		t.Errorf("sent %d SetPower packets to a switch", n)
+	}
+}
+
+func TestRelaysRejected(t *testing.T) {
+	_, device := newFakeSwitch(t)
+	if err := device.SetRelayPower(relayCount, true); err == nil {
+		t.Error("SetRelayPower accepted an index past the last relay")
+	}
+
+	fake := newFakeDevice(t, 2)
+	c := newTestClient(t)
+	bulb := fake.device(c)
+
+	if _, err := bulb.GetRelays(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetRelays on a bulb returned %v, want ErrUnsupported", err)
+	}
+	if err := bulb.SetRelayPower(0, true); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetRelayPower on a bulb returned %v, want ErrUnsupported", err)
+	}
+}
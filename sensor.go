@@ -0,0 +1,43 @@
+package lifxlan
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GetAmbientLight returns the ambient light level measured by the device in lux.
+// Devices without a light sensor answer with StateUnhandled and ErrUnsupported
+// is returned. This is remembered, so later calls fail without a request.
+func (d *Device) GetAmbientLight() (float64, error) {
+	if !d.hasSensor() {
+		return 0, fmt.Errorf("%w: ambient light sensor on %s", ErrUnsupported, d.GetMACAddress())
+	}
+
+	var state StateAmbientLightMessage
+	if err := d.request(&SensorGetAmbientLightMessage{}, &state); err != nil {
+		if errors.Is(err, ErrUnsupported) {
+			d.mu.Lock()
+			d.noSensor = true
+			d.mu.Unlock()
+		}
+		return 0, err
+	}
+
+	lux := float64(state.Lux)
+
+	// Update the device's cached ambient light level
+	d.update(func(info *DeviceInfo) {
+		info.AmbientLight = lux
+	})
+
+	return lux, nil
+}
+
+// hasSensor reports whether the device may have an ambient light sensor,
+// which is false once it has answered that it doesn't
+func (d *Device) hasSensor() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return !d.noSensor
+}
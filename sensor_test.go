@@ -0,0 +1,71 @@
+package lifxlan
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetAmbientLight(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(SensorGetAmbientLight, &StateAmbientLightMessage{Lux: 120.5})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	lux, err := device.GetAmbientLight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lux != 120.5 || device.Info().AmbientLight != 120.5 {
+		t.Errorf("got %v lux, cached %v, want 120.5", lux, device.Info().AmbientLight)
+	}
+}
+
+func TestGetAmbientLightWithoutSensor(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(SensorGetAmbientLight, &StateUnhandledMessage{UnhandledType: uint16(SensorGetAmbientLight)})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	// The device answers straight away rather than letting the request time out
+	start := time.Now()
+	if _, err := device.GetAmbientLight(); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("GetAmbientLight returned %v, want ErrUnsupported", err)
+	}
+	if elapsed := time.Since(start); elapsed >= responseTimeout {
+		t.Errorf("GetAmbientLight took %v, want the StateUnhandled reply to end it", elapsed)
+	}
+
+	// Later calls and polls don't ask again
+	if _, err := device.GetAmbientLight(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("second GetAmbientLight returned %v, want ErrUnsupported", err)
+	}
+	if err := device.poll(PollOptions{AmbientLight: true}); err != nil {
+		t.Errorf("poll returned %v, want the missing sensor to be skipped", err)
+	}
+
+	if n := len(sentPayloads(t, fake, device, SensorGetAmbientLight)); n != 1 {
+		t.Errorf("device was asked for ambient light %d times, want 1", n)
+	}
+}
+
+func TestStateUnhandledForOtherMessageIsIgnored(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetLabel,
+		&StateUnhandledMessage{UnhandledType: uint16(GetPower)},
+		&StateLabelMessage{Label: labelBytes("Fake")},
+	)
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	label, err := device.GetLabel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label != "Fake" {
+		t.Errorf("label %q, want %q", label, "Fake")
+	}
+}
@@ -0,0 +1,259 @@
+package lifxlan
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDetachedTile is returned by Tile methods on a tile that didn't come from Device.Tiles
+var ErrDetachedTile = errors.New("tile is not attached to a device")
+
+// maxTilePixels is the number of pixels carried by a single Set64 or State64 message
+const maxTilePixels = 64
+
+// TileOrientation is how a tile is mounted, derived from its accelerometer
+type TileOrientation int
+
+const (
+	TileUpright TileOrientation = iota
+	TileRotatedLeft
+	TileRotatedRight
+	TileFaceUp
+	TileFaceDown
+	TileUpsideDown
+)
+
+// String returns the name of the orientation
+func (o TileOrientation) String() string {
+	switch o {
+	case TileUpright:
+		return "upright"
+	case TileRotatedLeft:
+		return "rotated left"
+	case TileRotatedRight:
+		return "rotated right"
+	case TileFaceUp:
+		return "face up"
+	case TileFaceDown:
+		return "face down"
+	case TileUpsideDown:
+		return "upside down"
+	}
+	return fmt.Sprintf("TileOrientation(%d)", int(o))
+}
+
+// Tile is one of the matrix devices in the chain of a device. Tiles are
+// returned by Device.Tiles, the methods of a Tile built any other way fail
+// with ErrDetachedTile.
+type Tile struct {
+	Index       int             // Position in the chain
+	Width       int             // Width in pixels
+	Height      int             // Height in pixels
+	UserX       float32         // Position of the tile set by the user, in tile widths
+	UserY       float32         // Position of the tile set by the user, in tile heights
+	AccelX      int16           // Raw accelerometer measurement along the X axis
+	AccelY      int16           // Raw accelerometer measurement along the Y axis
+	AccelZ      int16           // Raw accelerometer measurement along the Z axis
+	Orientation TileOrientation // Orientation derived from the accelerometer
+
+	device *Device
+}
+
+// hasMatrix reports whether a product has a matrix of pixels
+func hasMatrix(f Features) bool { return f.Matrix }
+
+// tileOrientation derives the orientation of a tile from the gravity vector
+// (https://lan.developer.lifx.com/docs/tile-control#tile-orientation)
+func tileOrientation(x, y, z int16) TileOrientation {
+	// Devices without an accelerometer report -1 on every axis
+	if x == -1 && y == -1 && z == -1 {
+		return TileUpright
+	}
+
+	absX, absY, absZ := abs16(x), abs16(y), abs16(z)
+	switch {
+	case absX > absY && absX > absZ:
+		if x > 0 {
+			return TileRotatedRight
+		}
+		return TileRotatedLeft
+	case absZ > absX && absZ > absY:
+		if z > 0 {
+			return TileFaceDown
+		}
+		return TileFaceUp
+	case y > 0:
+		return TileUpsideDown
+	}
+	return TileUpright
+}
+
+// abs16 returns the absolute value of v as an int to avoid overflow
+func abs16(v int16) int {
+	if v < 0 {
+		return -int(v)
+	}
+	return int(v)
+}
+
+// Tiles returns the tiles in the chain of a matrix device
+func (d *Device) Tiles() ([]Tile, error) {
+	if err := d.require("matrix", hasMatrix); err != nil {
+		return nil, err
+	}
+
+	var state StateDeviceChainMessage
+	if err := d.request(&GetDeviceChainMessage{}, &state); err != nil {
+		return nil, err
+	}
+
+	count := min(int(state.TileDevicesCount), len(state.TileDevices))
+	tiles := make([]Tile, 0, count)
+	for i, t := range state.TileDevices[:count] {
+		tiles = append(tiles, Tile{
+			Index:       int(state.StartIndex) + i,
+			Width:       int(t.Width),
+			Height:      int(t.Height),
+			UserX:       t.UserX,
+			UserY:       t.UserY,
+			AccelX:      t.AccelMeasX,
+			AccelY:      t.AccelMeasY,
+			AccelZ:      t.AccelMeasZ,
+			Orientation: tileOrientation(t.AccelMeasX, t.AccelMeasY, t.AccelMeasZ),
+			device:      d,
+		})
+	}
+
+	return tiles, nil
+}
+
+// Get64 returns up to 64 pixels of a tile inside the given rectangle, row by row
+func (d *Device) Get64(tileIndex uint8, rect TileBufferRect) ([]LIFXColor, error) {
+	if err := d.require("matrix", hasMatrix); err != nil {
+		return nil, err
+	}
+
+	var state State64Message
+	err := d.collect(&Get64Message{TileIndex: tileIndex, Length: 1, Rect: rect}, waiterQueue, func(h *Header, payload []byte) (bool, error) {
+		if h.Type() != State64 {
+			return false, nil
+		}
+		if err := state.UnmarshalBinary(payload); err != nil {
+			return false, err
+		}
+
+		// Skip responses for other tiles or rows
+		return state.TileIndex == tileIndex && state.Rect.Y == rect.Y, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return state.Colors[:], nil
+}
+
+// Set64 sets up to 64 pixels of length tiles starting at tileIndex, filling
+// the given rectangle of frame buffer rect.FbIndex row by row
+func (d *Device) Set64(tileIndex, length uint8, rect TileBufferRect, colors []LIFXColor, duration time.Duration) error {
+	if err := d.require("matrix", hasMatrix); err != nil {
+		return err
+	}
+	if len(colors) > maxTilePixels {
+		return fmt.Errorf("too many pixels: %d, at most %d fit in one packet", len(colors), maxTilePixels)
+	}
+
+	msg := &Set64Message{
+		TileIndex: tileIndex,
+		Length:    length,
+		Rect:      rect,
+		Duration:  uint32(duration.Milliseconds()),
+	}
+	copy(msg.Colors[:], colors)
+
+	return d.send(msg)
+}
+
+// SetUserPosition records where a tile is placed relative to the others, in tile widths and heights
+func (d *Device) SetUserPosition(tileIndex uint8, x, y float32) error {
+	if err := d.require("matrix", hasMatrix); err != nil {
+		return err
+	}
+
+	return d.send(&SetUserPositionMessage{TileIndex: tileIndex, UserX: x, UserY: y})
+}
+
+// rowsPerPacket returns how many rows of the tile fit in a single 64 pixel message
+func (t *Tile) rowsPerPacket() int {
+	if t.Width <= 0 || t.Width > maxTilePixels {
+		return 0
+	}
+	return maxTilePixels / t.Width
+}
+
+// GetPixels returns the pixels of the tile row by row, Width*Height colors in total
+func (t *Tile) GetPixels() ([]LIFXColor, error) {
+	if t.device == nil {
+		return nil, ErrDetachedTile
+	}
+
+	rows := t.rowsPerPacket()
+	if rows == 0 {
+		return nil, fmt.Errorf("unsupported tile width %d", t.Width)
+	}
+
+	pixels := make([]LIFXColor, 0, t.Width*t.Height)
+	for y := 0; y < t.Height; y += rows {
+		rect := TileBufferRect{X: 0, Y: uint8(y), Width: uint8(t.Width)}
+		colors, err := t.device.Get64(uint8(t.Index), rect)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows %d of tile %d: %w", y, t.Index, err)
+		}
+
+		n := min(rows, t.Height-y) * t.Width
+		pixels = append(pixels, colors[:n]...)
+	}
+
+	return pixels, nil
+}
+
+// SetPixels sets the pixels of the tile row by row from Width*Height colors
+func (t *Tile) SetPixels(pixels []LIFXColor, duration time.Duration) error {
+	if t.device == nil {
+		return ErrDetachedTile
+	}
+
+	rows := t.rowsPerPacket()
+	if rows == 0 {
+		return fmt.Errorf("unsupported tile width %d", t.Width)
+	}
+	if len(pixels) != t.Width*t.Height {
+		return fmt.Errorf("got %d pixels for a %dx%d tile", len(pixels), t.Width, t.Height)
+	}
+
+	for y := 0; y < t.Height; y += rows {
+		start := y * t.Width
+		end := min(y+rows, t.Height) * t.Width
+
+		rect := TileBufferRect{X: 0, Y: uint8(y), Width: uint8(t.Width)}
+		if err := t.device.Set64(uint8(t.Index), 1, rect, pixels[start:end], duration); err != nil {
+			return fmt.Errorf("failed to set rows %d of tile %d: %w", y, t.Index, err)
+		}
+	}
+
+	return nil
+}
+
+// SetUserPosition records where the tile is placed relative to the others
+func (t *Tile) SetUserPosition(x, y float32) error {
+	if t.device == nil {
+		return ErrDetachedTile
+	}
+
+	if err := t.device.SetUserPosition(uint8(t.Index), x, y); err != nil {
+		return err
+	}
+
+	t.UserX, t.UserY = x, y
+	return nil
+}
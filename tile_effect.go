@@ -0,0 +1,107 @@
+package lifxlan
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// maxPaletteColors is the number of colors in a tile effect palette
+const maxPaletteColors = 16
+
+// TileEffect is a firmware effect that runs on a matrix device without
+// further traffic from the client
+type TileEffect struct {
+	Type     TileEffectType // TileEffectTypeOff, Morph, Flame or Sky
+	Speed    time.Duration  // Duration of one cycle of the effect
+	Duration time.Duration  // How long the effect runs, zero runs until it is stopped
+	Palette  []LIFXColor    // Up to 16 colors the Morph effect moves between
+
+	// Sky effect parameters
+	SkyType            TileEffectSkyType // Sunrise, sunset or clouds
+	CloudSaturationMin uint8             // Least saturated clouds, from 0 to 255
+	CloudSaturationMax uint8             // Most saturated clouds, from 0 to 255
+}
+
+// SetTileEffect starts or stops a firmware effect on a matrix device
+func (d *Device) SetTileEffect(effect TileEffect) error {
+	if err := d.require("matrix", hasMatrix); err != nil {
+		return err
+	}
+
+	switch effect.Type {
+	case TileEffectTypeOff, TileEffectTypeMorph, TileEffectTypeFlame, TileEffectTypeSky:
+	default:
+		return fmt.Errorf("invalid tile effect %s", effect.Type)
+	}
+	if len(effect.Palette) > maxPaletteColors {
+		return fmt.Errorf("too many palette colors: %d, at most %d are supported", len(effect.Palette), maxPaletteColors)
+	}
+
+	settings := TileEffectSettings{
+		Instanceid: rand.Uint32(), // Identifies this run of the effect
+		Type:       effect.Type,
+		Speed:      uint32(effect.Speed.Milliseconds()),
+		Duration:   uint64(effect.Duration.Nanoseconds()),
+		Parameters: TileEffectParameter{
+			SkyType:            effect.SkyType,
+			CloudSaturationMin: effect.CloudSaturationMin,
+			CloudSaturationMax: effect.CloudSaturationMax,
+		},
+		PaletteCount: uint8(len(effect.Palette)),
+	}
+	copy(settings.Palette[:], effect.Palette)
+
+	return d.send(&SetTileEffectMessage{Settings: settings})
+}
+
+// StartMorphEffect moves a palette of up to 16 colors across the tiles
+func (d *Device) StartMorphEffect(speed time.Duration, palette ...LIFXColor) error {
+	return d.SetTileEffect(TileEffect{Type: TileEffectTypeMorph, Speed: speed, Palette: palette})
+}
+
+// StartFlameEffect shows flames rising up the tiles
+func (d *Device) StartFlameEffect(speed time.Duration) error {
+	return d.SetTileEffect(TileEffect{Type: TileEffectTypeFlame, Speed: speed})
+}
+
+// StartSkyEffect shows a sunrise, sunset or clouds
+func (d *Device) StartSkyEffect(skyType TileEffectSkyType, speed time.Duration) error {
+	return d.SetTileEffect(TileEffect{
+		Type:               TileEffectTypeSky,
+		Speed:              speed,
+		SkyType:            skyType,
+		CloudSaturationMin: 50,
+		CloudSaturationMax: 180,
+	})
+}
+
+// StopTileEffect stops the running firmware effect
+func (d *Device) StopTileEffect() error {
+	return d.SetTileEffect(TileEffect{Type: TileEffectTypeOff})
+}
+
+// GetTileEffect returns the firmware effect running on a matrix device
+func (d *Device) GetTileEffect() (TileEffect, error) {
+	if err := d.require("matrix", hasMatrix); err != nil {
+		return TileEffect{}, err
+	}
+
+	var state StateTileEffectMessage
+	if err := d.request(&GetTileEffectMessage{}, &state); err != nil {
+		return TileEffect{}, err
+	}
+
+	settings := state.Settings
+	count := min(int(settings.PaletteCount), maxPaletteColors)
+
+	return TileEffect{
+		Type:               settings.Type,
+		Speed:              time.Duration(settings.Speed) * time.Millisecond,
+		Duration:           time.Duration(settings.Duration),
+		Palette:            append([]LIFXColor(nil), settings.Palette[:count]...),
+		SkyType:            settings.Parameters.SkyType,
+		CloudSaturationMin: settings.Parameters.CloudSaturationMin,
+		CloudSaturationMax: settings.Parameters.CloudSaturationMax,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package lifxlan
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTileEffect(t *testing.T) {
+	red := NewColor(0, 65535, 65535, 3500)
+	blue := NewColor(43690, 65535, 65535, 3500)
+
+	state := TileEffectSettings{
+		Type:         TileEffectTypeMorph,
+		Speed:        4000,
+		Duration:     uint64(90 * time.Second),
+		PaletteCount: 2,
+	}
+	state.Palette[0], state.Palette[1] = red, blue
+
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 55})
+	fake.reply(GetTileEffect, &StateTileEffectMessage{Settings: state})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	err := device.SetTileEffect(TileEffect{
+		Type:               TileEffectTypeSky,
+		Speed:              10 * time.Second,
+		Duration:           time.Minute,
+		Palette:            []LIFXColor{red},
+		SkyType:            TileEffectSkyTypeClouds,
+		CloudSaturationMin: 50,
+		CloudSaturationMax: 180,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payloads := sentPayloads(t, fake, device, SetTileEffect)
+	if len(payloads) != 1 {
+		t.Fatalf("sent %d SetTileEffect packets, want 1", len(payloads))
+	}
+	var msg SetTileEffectMessage
+	if err := msg.UnmarshalBinary(payloads[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Speed is sent in milliseconds and duration in nanoseconds
+	settings := msg.Settings
+	settings.Instanceid = 0
+	want := TileEffectSettings{
+		Type:     TileEffectTypeSky,
+		Speed:    10000,
+		Duration: 60_000_000_000,
+		Parameters: TileEffectParameter{
+			SkyType:            TileEffectSkyTypeClouds,
+			CloudSaturationMin: 50,
+			CloudSaturationMax: 180,
+		},
+		PaletteCount: 1,
+	}
+	want.Palette[0] = red
+	if settings != want {
+		t.Errorf("sent settings %+v, want %+v", settings, want)
+	}
+
+	effect, err := device.GetTileEffect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if effect.Type != TileEffectTypeMorph || effect.Speed != 4*time.Second || effect.Duration != 90*time.Second {
+		t.Errorf("got %v effect at %v for %v, want morph at 4s for 1m30s", effect.Type, effect.Speed, effect.Duration)
+	}
+	if len(effect.Palette) != 2 || effect.Palette[0] != red || effect.Palette[1] != blue {
+		t.Errorf("got palette %v, want %v", effect.Palette, []LIFXColor{red, blue})
+	}
+}
+
+func TestTileEffectRejectsInvalid(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 55})
+
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	if err := device.SetTileEffect(TileEffect{Type: TileEffectTypeReserved1}); err == nil {
+		t.Error("SetTileEffect accepted a reserved effect")
+	}
+	if err := device.StartMorphEffect(time.Second, make([]LIFXColor, maxPaletteColors+1)...); err == nil {
+		t.Error("StartMorphEffect accepted 17 palette colors")
+	}
+	if n := len(sentPayloads(t, fake, device, SetTileEffect)); n != 0 {
+		t.Errorf("sent %d SetTileEffect packets for invalid effects", n)
+	}
+}
+
+func TestTileEffectUnsupported(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	if err := device.StartFlameEffect(time.Second); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("StartFlameEffect returned %v, want ErrUnsupported", err)
+	}
+	if _, err := device.GetTileEffect(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetTileEffect returned %v, want ErrUnsupported", err)
+	}
+	if n := len(sentPayloads(t, fake, device, SetTileEffect)); n != 0 {
+		t.Errorf("sent %d SetTileEffect packets to a bulb", n)
+	}
+}
@@ -0,0 +1,164 @@
+package lifxlan
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTileOrientation(t *testing.T) {
+	tests := []struct {
+		x, y, z int16
+		want    TileOrientation
+	}{
+		{0, -1000, 0, TileUpright},
+		{0, 1000, 0, TileUpsideDown},
+		{-1000, 0, 0, TileRotatedLeft},
+		{1000, 0, 0, TileRotatedRight},
+		{0, 0, -1000, TileFaceUp},
+		{0, 0, 1000, TileFaceDown},
+		{-32768, 0, 0, TileRotatedLeft},
+		{-1, -1, -1, TileUpright}, // No accelerometer
+	}
+
+	for _, tt := range tests {
+		if got := tileOrientation(tt.x, tt.y, tt.z); got != tt.want {
+			t.Errorf("tileOrientation(%d, %d, %d) = %v, want %v", tt.x, tt.y, tt.z, got, tt.want)
+		}
+	}
+}
+
+// tileSizes are the tile dimensions exercised by the pixel tests, with the
+// rows each Get64 or Set64 packet starts at
+var tileSizes = []struct {
+	width, height int
+	rows          []uint8
+}{
+	{8, 8, []uint8{0}},
+	{16, 8, []uint8{0, 4}},
+	{5, 6, []uint8{0}},
+	{5, 15, []uint8{0, 12}},
+}
+
+// newFakeTile returns a tile of a fake LIFX Tile whose Get64 replies number
+// each pixel from the start of the tile in its hue
+func newFakeTile(t *testing.T, width, height int) (*fakeDevice, *Tile) {
+	fake := newFakeDevice(t, 1)
+	fake.reply(GetVersion, &StateVersionMessage{Vendor: 1, Product: 55})
+	fake.handle(Get64, func(payload []byte) []Message {
+		var req Get64Message
+		if err := req.UnmarshalBinary(payload); err != nil {
+			return nil
+		}
+
+		state := &State64Message{TileIndex: req.TileIndex, Rect: req.Rect}
+		for i := range state.Colors {
+			state.Colors[i] = NewColor(uint16(int(req.Rect.Y)*int(req.Rect.Width)+i), 0, 0, 3500)
+		}
+		return []Message{state}
+	})
+
+	c := newTestClient(t)
+	tile := &Tile{Index: 2, Width: width, Height: height, device: fake.device(c)}
+
+	return fake, tile
+}
+
+func TestTileGetPixels(t *testing.T) {
+	for _, tt := range tileSizes {
+		t.Run(fmt.Sprintf("%dx%d", tt.width, tt.height), func(t *testing.T) {
+			fake, tile := newFakeTile(t, tt.width, tt.height)
+
+			pixels, err := tile.GetPixels()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(pixels) != tt.width*tt.height {
+				t.Fatalf("got %d pixels, want %d", len(pixels), tt.width*tt.height)
+			}
+			for i, pixel := range pixels {
+				if want := NewColor(uint16(i), 0, 0, 3500); pixel != want {
+					t.Fatalf("pixel %d is %v, want %v", i, pixel, want)
+				}
+			}
+
+			payloads := sentPayloads(t, fake, tile.device, Get64)
+			if len(payloads) != len(tt.rows) {
+				t.Fatalf("sent %d Get64 packets, want %d", len(payloads), len(tt.rows))
+			}
+			for i, payload := range payloads {
+				var msg Get64Message
+				if err := msg.UnmarshalBinary(payload); err != nil {
+					t.Fatal(err)
+				}
+
+				want := TileBufferRect{Y: tt.rows[i], Width: uint8(tt.width)}
+				if msg.TileIndex != 2 || msg.Rect != want {
+					t.Errorf("packet %d reads tile %d at %+v, want tile 2 at %+v", i, msg.TileIndex, msg.Rect, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTileSetPixels(t *testing.T) {
+	for _, tt := range tileSizes {
+		t.Run(fmt.Sprintf("%dx%d", tt.width, tt.height), func(t *testing.T) {
+			fake, tile := newFakeTile(t, tt.width, tt.height)
+
+			pixels := make([]LIFXColor, tt.width*tt.height)
+			for i := range pixels {
+				pixels[i] = NewColor(uint16(i), 65535, 65535, 3500)
+			}
+
+			if err := tile.SetPixels(pixels, 0); err != nil {
+				t.Fatal(err)
+			}
+
+			payloads := sentPayloads(t, fake, tile.device, Set64)
+			if len(payloads) != len(tt.rows) {
+				t.Fatalf("sent %d Set64 packets, want %d", len(payloads), len(tt.rows))
+			}
+			for i, payload := range payloads {
+				var msg Set64Message
+				if err := msg.UnmarshalBinary(payload); err != nil {
+					t.Fatal(err)
+				}
+
+				want := TileBufferRect{Y: tt.rows[i], Width: uint8(tt.width)}
+				if msg.TileIndex != 2 || msg.Length != 1 || msg.Rect != want {
+					t.Errorf("packet %d sets %d tiles from %d at %+v, want 1 tile from 2 at %+v",
+						i, msg.Length, msg.TileIndex, msg.Rect, want)
+				}
+
+				// Each packet carries its slice of the pixels, padded with zero colors
+				start := int(tt.rows[i]) * tt.width
+				end := min(start+maxTilePixels/tt.width*tt.width, len(pixels))
+				for j, color := range msg.Colors {
+					var want LIFXColor
+					if j < end-start {
+						want = pixels[start+j]
+					}
+					if color != want {
+						t.Fatalf("packet %d pixel %d is %v, want %v", i, j, color, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDetachedTile(t *testing.T) {
+	var tile Tile
+
+	if _, err := tile.GetPixels(); !errors.Is(err, ErrDetachedTile) {
+		t.Errorf("GetPixels returned %v, want ErrDetachedTile", err)
+	}
+	if err := tile.SetPixels(nil, 0); !errors.Is(err, ErrDetachedTile) {
+		t.Errorf("SetPixels returned %v, want ErrDetachedTile", err)
+	}
+	if err := tile.SetUserPosition(1, 0); !errors.Is(err, ErrDetachedTile) {
+		t.Errorf("SetUserPosition returned %v, want ErrDetachedTile", err)
+	}
+}
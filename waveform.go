@@ -0,0 +1,114 @@
+package lifxlan
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Waveform is an effect run by the device firmware that moves between the
+// current color and a target color (https://lan.developer.lifx.com/docs/waveforms)
+type Waveform struct {
+	Shape     LightWaveform // LightWaveformSaw, Sine, HalfSine, Triangle or Pulse
+	Color     LIFXColor     // Color to move towards
+	Period    time.Duration // Duration of a single cycle
+	Cycles    float32       // Number of cycles, fractions stop part way through a cycle
+	SkewRatio float64       // 0 to 1, the share of each cycle spent at the original color, zero uses 0.5
+	Transient bool          // Return to the original color when the effect finishes
+
+	// Channels limits the effect to some of the color channels. When nil every
+	// channel moves towards Color.
+	Channels *WaveformChannels
+}
+
+// WaveformChannels selects the color channels a waveform changes
+type WaveformChannels struct {
+	Hue        bool
+	Saturation bool
+	Brightness bool
+	Kelvin     bool
+}
+
+// skewRatio converts a 0 to 1 ratio to the signed protocol value
+func skewRatio(ratio float64) int16 {
+	if ratio == 0 {
+		ratio = 0.5
+	}
+	ratio = math.Max(0, math.Min(1, ratio))
+
+	return int16(math.Round(ratio*65535) - 32768)
+}
+
+// message returns the SetWaveform or SetWaveformOptional message for the waveform
+func (w Waveform) message() (Message, error) {
+	if w.Shape > LightWaveformPulse {
+		return nil, fmt.Errorf("invalid waveform %s", w.Shape)
+	}
+
+	period := uint32(w.Period.Milliseconds())
+	skew := skewRatio(w.SkewRatio)
+
+	if w.Channels == nil {
+		return &SetWaveformMessage{
+			Transient: w.Transient,
+			Color:     w.Color,
+			Period:    period,
+			Cycles:    w.Cycles,
+			SkewRatio: skew,
+			Waveform:  w.Shape,
+		}, nil
+	}
+
+	return &SetWaveformOptionalMessage{
+		Transient:     w.Transient,
+		Color:         w.Color,
+		Period:        period,
+		Cycles:        w.Cycles,
+		SkewRatio:     skew,
+		Waveform:      w.Shape,
+		SetHue:        w.Channels.Hue,
+		SetSaturation: w.Channels.Saturation,
+		SetBrightness: w.Channels.Brightness,
+		SetKelvin:     w.Channels.Kelvin,
+	}, nil
+}
+
+// SetWaveform starts a waveform effect on the device
+func (d *Device) SetWaveform(w Waveform) error {
+	msg, err := w.message()
+	if err != nil {
+		return err
+	}
+
+	return d.send(msg)
+}
+
+// Pulse switches between the current color and the given color for a number
+// of cycles, then returns to the current color
+func (d *Device) Pulse(color LIFXColor, period time.Duration, cycles float32) error {
+	return d.SetWaveform(Waveform{
+		Shape:     LightWaveformPulse,
+		Color:     color,
+		Period:    period,
+		Cycles:    cycles,
+		Transient: true,
+	})
+}
+
+// SetWaveform starts a waveform effect on the device that matches the given label
+func (c *Client) SetWaveform(label string, w Waveform) error {
+	device, err := c.GetDeviceByLabel(label)
+	if err != nil {
+		return err
+	}
+	return device.SetWaveform(w)
+}
+
+// Pulse pulses the device that matches the given label, see Device.Pulse
+func (c *Client) Pulse(label string, color LIFXColor, period time.Duration, cycles float32) error {
+	device, err := c.GetDeviceByLabel(label)
+	if err != nil {
+		return err
+	}
+	return device.Pulse(color, period, cycles)
+}
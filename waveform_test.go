@@ -0,0 +1,111 @@
+package lifxlan
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSkewRatio(t *testing.T) {
+	tests := []struct {
+		ratio float64
+		want  int16
+	}{
+		{0, 0}, // Zero uses the default of 0.5
+		{0.5, 0},
+		{0.25, -16384},
+		{1, 32767},
+		{2, 32767},
+		{-1, -32768},
+	}
+
+	for _, tt := range tests {
+		if got := skewRatio(tt.ratio); got != tt.want {
+			t.Errorf("skewRatio(%v) = %d, want %d", tt.ratio, got, tt.want)
+		}
+	}
+}
+
+func TestSetWaveform(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	color := NewColor(0, 65535, 65535, 3500)
+	w := Waveform{
+		Shape:     LightWaveformSine,
+		Color:     color,
+		Period:    2 * time.Second,
+		Cycles:    3.5,
+		Transient: true,
+	}
+	if err := device.SetWaveform(w); err != nil {
+		t.Fatal(err)
+	}
+
+	payloads := sentPayloads(t, fake, device, SetWaveform)
+	if len(payloads) != 1 {
+		t.Fatalf("sent %d SetWaveform packets, want 1", len(payloads))
+	}
+
+	var msg SetWaveformMessage
+	if err := msg.UnmarshalBinary(payloads[0]); err != nil {
+		t.Fatal(err)
+	}
+	want := SetWaveformMessage{Transient: true, Color: color, Period: 2000, Cycles: 3.5, SkewRatio: 0, Waveform: LightWaveformSine}
+	if msg != want {
+		t.Errorf("sent %+v, want %+v", msg, want)
+	}
+}
+
+func TestSetWaveformOptional(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	color := NewColor(0, 0, 65535, 3500)
+	w := Waveform{
+		Shape:     LightWaveformPulse,
+		Color:     color,
+		Period:    500 * time.Millisecond,
+		Cycles:    10,
+		SkewRatio: 1,
+		Channels:  &WaveformChannels{Brightness: true},
+	}
+	if err := device.SetWaveform(w); err != nil {
+		t.Fatal(err)
+	}
+
+	payloads := sentPayloads(t, fake, device, SetWaveformOptional)
+	if len(payloads) != 1 {
+		t.Fatalf("sent %d SetWaveformOptional packets, want 1", len(payloads))
+	}
+
+	var msg SetWaveformOptionalMessage
+	if err := msg.UnmarshalBinary(payloads[0]); err != nil {
+		t.Fatal(err)
+	}
+	want := SetWaveformOptionalMessage{
+		Color:         color,
+		Period:        500,
+		Cycles:        10,
+		SkewRatio:     32767,
+		Waveform:      LightWaveformPulse,
+		SetBrightness: true,
+	}
+	if msg != want {
+		t.Errorf("sent %+v, want %+v", msg, want)
+	}
+}
+
+func TestSetWaveformRejectsUnknownShape(t *testing.T) {
+	fake := newFakeDevice(t, 1)
+	c := newTestClient(t)
+	device := fake.device(c)
+
+	if err := device.SetWaveform(Waveform{Shape: LightWaveformPulse + 1}); err == nil {
+		t.Error("SetWaveform accepted an unknown shape")
+	}
+	if n := len(sentPayloads(t, fake, device, SetWaveform)); n != 0 {
+		t.Errorf("sent %d SetWaveform packets for an unknown shape", n)
+	}
+}
@@ -0,0 +1,104 @@
+package lifxlan
+
+import (
+	"fmt"
+	"math"
+)
+
+// SignalQuality is a rating of Wi-Fi signal strength
+type SignalQuality int
+
+const (
+	SignalNone SignalQuality = iota
+	SignalVeryBad
+	SignalSomewhatBad
+	SignalAlright
+	SignalGood
+)
+
+// String returns a description of the signal quality
+func (q SignalQuality) String() string {
+	switch q {
+	case SignalNone:
+		return "none"
+	case SignalVeryBad:
+		return "very bad"
+	case SignalSomewhatBad:
+		return "somewhat bad"
+	case SignalAlright:
+		return "alright"
+	case SignalGood:
+		return "good"
+	}
+	return fmt.Sprintf("SignalQuality(%d)", int(q))
+}
+
+// MarshalText encodes the signal quality as its description
+func (q SignalQuality) MarshalText() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// WifiInfo describes the Wi-Fi signal received by a device
+type WifiInfo struct {
+	Signal  float32       `json:"signal"`  // Raw signal value reported by the device
+	RSSI    int           `json:"rssi"`    // Signal strength in dBm
+	Quality SignalQuality `json:"quality"` // Rating of the signal strength
+}
+
+// newWifiInfo converts the signal of a StateWifiInfo packet
+// (https://lan.developer.lifx.com/docs/information-messages#statewifiinfo---packet-17)
+func newWifiInfo(signal float32) WifiInfo {
+	info := WifiInfo{Signal: signal}
+	if signal <= 0 || math.IsNaN(float64(signal)) || math.IsInf(float64(signal), 0) {
+		return info // No signal
+	}
+
+	info.RSSI = int(math.Floor(10*math.Log10(float64(signal)) + 0.5))
+	info.Quality = signalQuality(info.RSSI)
+
+	return info
+}
+
+// signalQuality rates an RSSI following the LIFX documentation. Older firmware
+// reports a positive signal to noise ratio instead of a negative RSSI, which is
+// rated on its own scale. The documentation reserves an RSSI of 200 to mean no
+// signal, which newWifiInfo produces from a reported signal of 1e20.
+func signalQuality(rssi int) SignalQuality {
+	if rssi == 200 {
+		return SignalNone
+	}
+
+	if rssi < 0 {
+		switch {
+		case rssi <= -80:
+			return SignalVeryBad
+		case rssi <= -70:
+			return SignalSomewhatBad
+		case rssi <= -60:
+			return SignalAlright
+		}
+		return SignalGood
+	}
+
+	switch {
+	case rssi >= 4 && rssi <= 6:
+		return SignalVeryBad
+	case rssi >= 7 && rssi <= 11:
+		return SignalSomewhatBad
+	case rssi >= 12 && rssi <= 16:
+		return SignalAlright
+	case rssi > 16:
+		return SignalGood
+	}
+	return SignalNone
+}
+
+// GetWifiInfo returns the Wi-Fi signal strength received by the device
+func (d *Device) GetWifiInfo() (WifiInfo, error) {
+	var state StateWifiInfoMessage
+	if err := d.request(&GetWifiInfoMessage{}, &state); err != nil {
+		return WifiInfo{}, err
+	}
+
+	return newWifiInfo(state.Signal), nil
+}
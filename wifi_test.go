@@ -0,0 +1,72 @@
+package lifxlan
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewWifiInfo(t *testing.T) {
+	tests := []struct {
+		signal  float32
+		rssi    int
+		quality SignalQuality
+	}{
+		{0, 0, SignalNone},
+		{-1, 0, SignalNone},
+		{float32(math.NaN()), 0, SignalNone},
+		{float32(math.Inf(1)), 0, SignalNone},
+		{1e-9, -90, SignalVeryBad},
+		{1e-8, -80, SignalVeryBad},
+		{1e-7, -70, SignalSomewhatBad},
+		{1e-6, -60, SignalAlright},
+		{1e-5, -50, SignalGood},
+		{3.1e-8, -75, SignalSomewhatBad}, // 10*log10 is -75.09, rounded to the nearest dBm
+		{5, 7, SignalSomewhatBad},        // Signal to noise ratio from older firmware
+		{100, 20, SignalGood},
+		{1e20, 200, SignalNone},
+	}
+
+	for _, tt := range tests {
+		info := newWifiInfo(tt.signal)
+		if info.RSSI != tt.rssi || info.Quality != tt.quality {
+			t.Errorf("newWifiInfo(%g) = %d dBm %v, want %d dBm %v", tt.signal, info.RSSI, info.Quality, tt.rssi, tt.quality)
+		}
+	}
+}
+
+func TestSignalQuality(t *testing.T) {
+	tests := []struct {
+		rssi int
+		want SignalQuality
+	}{
+		// RSSI in dBm
+		{-81, SignalVeryBad},
+		{-80, SignalVeryBad},
+		{-79, SignalSomewhatBad},
+		{-70, SignalSomewhatBad},
+		{-69, SignalAlright},
+		{-60, SignalAlright},
+		{-59, SignalGood},
+		{-1, SignalGood},
+
+		// Signal to noise ratio
+		{0, SignalNone},
+		{3, SignalNone},
+		{4, SignalVeryBad},
+		{6, SignalVeryBad},
+		{7, SignalSomewhatBad},
+		{11, SignalSomewhatBad},
+		{12, SignalAlright},
+		{16, SignalAlright},
+		{17, SignalGood},
+
+		// Reserved for no signal
+		{200, SignalNone},
+	}
+
+	for _, tt := range tests {
+		if got := signalQuality(tt.rssi); got != tt.want {
+			t.Errorf("signalQuality(%d) = %v, want %v", tt.rssi, got, tt.want)
+		}
+	}
+}